@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/duolacloud/crud-core/types"
+)
+
+const (
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 10 * time.Second
+)
+
+const (
+	breakerClosed = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// 熔断器配置
+type CircuitBreakerSettings struct {
+	FailureThreshold int           // 连续失败多少次后熔断，默认 5
+	Cooldown         time.Duration // 熔断持续时间，之后放行一个探测请求，默认 10s
+}
+
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    int
+	failures int
+	openedAt time.Time
+	probing  bool
+	now      func() time.Time
+}
+
+func newCircuitBreaker(settings CircuitBreakerSettings) *circuitBreaker {
+	b := &circuitBreaker{
+		threshold: settings.FailureThreshold,
+		cooldown:  settings.Cooldown,
+		now:       time.Now,
+	}
+	if b.threshold <= 0 {
+		b.threshold = defaultBreakerThreshold
+	}
+	if b.cooldown <= 0 {
+		b.cooldown = defaultBreakerCooldown
+	}
+	return b
+}
+
+// 判断请求是否可以发往 redis，熔断期间返回 ErrCircuitOpen。
+// 冷却结束后进入半开状态，同一时间只放行一个探测请求
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = breakerHalfOpen
+		b.probing = true
+		return nil
+	case breakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	}
+	return nil
+}
+
+// 记录一次请求的结果，未命中不算失败，调用方取消的请求不影响熔断状态
+func (b *circuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.probing = false
+	}
+
+	if errors.Is(err, ErrCanceled) {
+		return
+	}
+
+	if err == nil || errors.Is(err, types.ErrNotFound) {
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// 未设置熔断器时直接放行
+func (rc *RedisCache) breakerAllow() error {
+	if rc.breaker == nil {
+		return nil
+	}
+	return rc.breaker.allow()
+}
+
+func (rc *RedisCache) breakerRecord(err error) {
+	if rc.breaker != nil {
+		rc.breaker.record(err)
+	}
+}
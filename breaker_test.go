@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/duolacloud/crud-core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	b := newCircuitBreaker(CircuitBreakerSettings{FailureThreshold: 2, Cooldown: time.Second})
+	b.now = func() time.Time { return now }
+
+	failure := errors.New("connection refused")
+
+	assert.Nil(t, b.allow())
+	b.record(failure)
+	assert.Nil(t, b.allow())
+	b.record(types.ErrNotFound)
+	assert.Nil(t, b.allow())
+	b.record(failure)
+	assert.Nil(t, b.allow())
+	b.record(failure)
+	assert.ErrorIs(t, b.allow(), ErrCircuitOpen)
+
+	// 冷却结束后只放行一个探测请求，探测失败重新熔断
+	now = now.Add(time.Second)
+	assert.Nil(t, b.allow())
+	assert.ErrorIs(t, b.allow(), ErrCircuitOpen)
+	b.record(failure)
+	assert.ErrorIs(t, b.allow(), ErrCircuitOpen)
+
+	// 探测成功后恢复
+	now = now.Add(time.Second)
+	assert.Nil(t, b.allow())
+	b.record(nil)
+	assert.Nil(t, b.allow())
+	assert.Nil(t, b.allow())
+}
@@ -0,0 +1,126 @@
+// cacheotel 提供基于 OpenTelemetry 的缓存操作钩子，单独成包，不使用追踪的程序不会引入 otel
+package cacheotel
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	rediscache "github.com/duolacloud/crud-cache-redis"
+	"github.com/duolacloud/crud-core/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/duolacloud/crud-cache-redis"
+
+// 为 Get / Set / Delete 记录 span 的钩子，通过 WithTracing 或 WithHook 注册：
+//
+//	rc := cache.New(cacheotel.WithTracing(provider))
+type Hook struct {
+	tracer  trace.Tracer
+	rawKeys bool
+}
+
+type Option func(*Hook)
+
+// 设置创建 tracer 的 TracerProvider，默认使用 otel.GetTracerProvider()
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(h *Hook) {
+		h.tracer = provider.Tracer(tracerName)
+	}
+}
+
+// 设置 span 中是否记录原始缓存键，默认只记录缓存键的摘要
+func WithRawKeys(raw bool) Option {
+	return func(h *Hook) {
+		h.rawKeys = raw
+	}
+}
+
+// 使用 provider 为 Get / Set / Delete 记录 span，provider 为空时使用 otel.GetTracerProvider()，
+// 等同于 WithHook(NewHook(WithTracerProvider(provider), opts...))
+func WithTracing(provider trace.TracerProvider, opts ...Option) rediscache.Option {
+	if provider != nil {
+		opts = append([]Option{WithTracerProvider(provider)}, opts...)
+	}
+	return rediscache.WithHook(NewHook(opts...))
+}
+
+func NewHook(opts ...Option) *Hook {
+	h := &Hook{}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.tracer == nil {
+		h.tracer = otel.GetTracerProvider().Tracer(tracerName)
+	}
+	return h
+}
+
+func (h *Hook) BeforeGet(ctx context.Context, key string) context.Context {
+	return h.start(ctx, "get", key)
+}
+
+func (h *Hook) AfterGet(ctx context.Context, key string, d time.Duration, err error) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Bool("cache.hit", err == nil))
+	end(span, err)
+}
+
+func (h *Hook) BeforeSet(ctx context.Context, key string) context.Context {
+	return h.start(ctx, "set", key)
+}
+
+func (h *Hook) AfterSet(ctx context.Context, key string, d time.Duration, err error) {
+	end(trace.SpanFromContext(ctx), err)
+}
+
+func (h *Hook) BeforeDelete(ctx context.Context, key string) context.Context {
+	return h.start(ctx, "delete", key)
+}
+
+func (h *Hook) AfterDelete(ctx context.Context, key string, d time.Duration, err error) {
+	end(trace.SpanFromContext(ctx), err)
+}
+
+// 记录 redis 中保存的值的字节数
+func (h *Hook) ObserveValueSize(ctx context.Context, key string, size int) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("cache.value_size", size))
+}
+
+func (h *Hook) start(ctx context.Context, op string, key string) context.Context {
+	ctx, _ = h.tracer.Start(ctx, "cache."+op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "redis"),
+			h.keyAttribute(key),
+		),
+	)
+	return ctx
+}
+
+// 默认只记录缓存键的 sha256 摘要，避免键中的敏感信息进入追踪系统
+func (h *Hook) keyAttribute(key string) attribute.KeyValue {
+	if h.rawKeys {
+		return attribute.String("cache.key", key)
+	}
+	sum := sha256.Sum256([]byte(key))
+	return attribute.String("cache.key_hash", hex.EncodeToString(sum[:8]))
+}
+
+// 结束 span，未命中和写入条件不满足不视为错误
+func end(span trace.Span, err error) {
+	if errors.Is(err, rediscache.ErrConditionFailed) {
+		span.SetAttributes(attribute.Bool("cache.skipped", true))
+	}
+	if err != nil && !errors.Is(err, types.ErrNotFound) && !errors.Is(err, rediscache.ErrConditionFailed) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
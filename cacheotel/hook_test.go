@@ -0,0 +1,120 @@
+package cacheotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	rediscache "github.com/duolacloud/crud-cache-redis"
+	"github.com/duolacloud/crud-core/cache"
+	"github.com/duolacloud/crud-core/types"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var (
+	_ rediscache.Hook          = (*Hook)(nil)
+	_ rediscache.ValueSizeHook = (*Hook)(nil)
+)
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestHook(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	redisCache, err := rediscache.New(
+		rediscache.WithPrefix("curd-cache-redis:"),
+		WithTracing(provider, WithRawKeys(true)),
+	)
+	assert.Nil(t, err)
+
+	err = redisCache.Delete(context.TODO(), "otel_key")
+	assert.Nil(t, err)
+	var name string
+	err = redisCache.Get(context.TODO(), "otel_key", &name)
+	assert.True(t, errors.Is(err, types.ErrNotFound))
+	err = redisCache.Set(context.TODO(), "otel_key", "jack", cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+	err = redisCache.Get(context.TODO(), "otel_key", &name)
+	assert.Nil(t, err)
+	err = redisCache.Set(context.TODO(), "otel_key", make(chan int))
+	assert.NotNil(t, err)
+
+	spans := recorder.Ended()
+	assert.Equal(t, 5, len(spans))
+	var names []string
+	for _, span := range spans {
+		names = append(names, span.Name())
+		attrs := attributes(span)
+		assert.Equal(t, "redis", attrs["db.system"].AsString())
+		assert.Equal(t, "curd-cache-redis:otel_key", attrs["cache.key"].AsString())
+	}
+	assert.Equal(t, []string{"cache.delete", "cache.get", "cache.set", "cache.get", "cache.set"}, names)
+
+	// 未命中不视为错误
+	assert.False(t, attributes(spans[1])["cache.hit"].AsBool())
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	assert.True(t, attributes(spans[3])["cache.hit"].AsBool())
+	// 读写的值大小，"jack" 按原样保存为 4 字节
+	assert.Equal(t, int64(4), attributes(spans[2])["cache.value_size"].AsInt64())
+	assert.Equal(t, int64(4), attributes(spans[3])["cache.value_size"].AsInt64())
+	assert.Equal(t, codes.Unset, spans[2].Status().Code)
+
+	assert.Equal(t, codes.Error, spans[4].Status().Code)
+	assert.Equal(t, 1, len(spans[4].Events()))
+}
+
+func TestHookKeyHash(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	h := NewHook(WithTracerProvider(provider))
+
+	ctx := h.BeforeSet(context.Background(), "user:1")
+	h.AfterSet(ctx, "user:1", time.Millisecond, nil)
+
+	spans := recorder.Ended()
+	assert.Equal(t, 1, len(spans))
+	attrs := attributes(spans[0])
+	assert.False(t, attrs["cache.key"].Type() == attribute.STRING)
+	assert.Equal(t, 16, len(attrs["cache.key_hash"].AsString()))
+}
+
+func TestHookWithoutTracer(t *testing.T) {
+	// 未设置 TracerProvider 时使用全局的 provider，默认不记录任何 span
+	redisCache, err := rediscache.New(
+		rediscache.WithPrefix("curd-cache-redis:"),
+		WithTracing(nil),
+	)
+	assert.Nil(t, err)
+
+	err = redisCache.Set(context.TODO(), "otel_noop", "jack", cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+	var name string
+	err = redisCache.Get(context.TODO(), "otel_noop", &name)
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", name)
+}
+
+func TestHookConditionFailed(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	h := NewHook(WithTracerProvider(provider))
+
+	ctx := h.BeforeSet(context.Background(), "user:1")
+	h.AfterSet(ctx, "user:1", time.Millisecond, rediscache.ErrConditionFailed)
+
+	spans := recorder.Ended()
+	assert.Equal(t, 1, len(spans))
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.True(t, attributes(spans[0])["cache.skipped"].AsBool())
+}
@@ -0,0 +1,89 @@
+// cacheprometheus 提供基于 prometheus 的缓存指标采集器，单独成包，不使用 prometheus 的程序不会引入 client_golang
+package cacheprometheus
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	opGet    = "get"
+	opSet    = "set"
+	opDelete = "delete"
+)
+
+// 实现了缓存的 Collector 和 prometheus.Collector，注册后即可使用
+//
+//	collector := cacheprometheus.NewCollector("app")
+//	prometheus.MustRegister(collector)
+//	c, err := cache.New(cache.WithMetrics(collector))
+type Collector struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	size     *prometheus.HistogramVec
+}
+
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cache",
+			Name:      "requests_total",
+			Help:      "Total number of cache operations by operation and result.",
+		}, []string{"op", "result"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "cache",
+			Name:      "duration_seconds",
+			Help:      "Latency of successful cache operations.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"op"}),
+		size: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "cache",
+			Name:      "value_size_bytes",
+			Help:      "Size of cached values by operation.",
+			Buckets:   prometheus.ExponentialBuckets(64, 4, 10),
+		}, []string{"op"}),
+	}
+}
+
+func (c *Collector) ObserveGet(hit bool, d time.Duration) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	c.requests.WithLabelValues(opGet, result).Inc()
+	c.duration.WithLabelValues(opGet).Observe(d.Seconds())
+}
+
+func (c *Collector) ObserveSet(d time.Duration) {
+	c.requests.WithLabelValues(opSet, "ok").Inc()
+	c.duration.WithLabelValues(opSet).Observe(d.Seconds())
+}
+
+func (c *Collector) ObserveDelete(d time.Duration) {
+	c.requests.WithLabelValues(opDelete, "ok").Inc()
+	c.duration.WithLabelValues(opDelete).Observe(d.Seconds())
+}
+
+func (c *Collector) ObserveError(op string) {
+	c.requests.WithLabelValues(op, "error").Inc()
+}
+
+func (c *Collector) ObserveValueSize(op string, bytes int) {
+	c.size.WithLabelValues(op).Observe(float64(bytes))
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.duration.Describe(ch)
+	c.size.Describe(ch)
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.duration.Collect(ch)
+	c.size.Collect(ch)
+}
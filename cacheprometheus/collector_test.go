@@ -0,0 +1,28 @@
+package cacheprometheus
+
+import (
+	"testing"
+	"time"
+
+	rediscache "github.com/duolacloud/crud-cache-redis"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ rediscache.Collector = (*Collector)(nil)
+
+func TestCollector(t *testing.T) {
+	c := NewCollector("app")
+	c.ObserveGet(true, time.Millisecond)
+	c.ObserveGet(false, time.Millisecond)
+	c.ObserveGet(false, time.Millisecond)
+	c.ObserveSet(time.Millisecond)
+	c.ObserveError("decode")
+	c.ObserveValueSize("set", 128)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.requests.WithLabelValues("get", "hit")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(c.requests.WithLabelValues("get", "miss")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.requests.WithLabelValues("set", "ok")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.requests.WithLabelValues("decode", "error")))
+	assert.Equal(t, 4, testutil.CollectAndCount(c, "app_cache_requests_total"))
+}
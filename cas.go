@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/duolacloud/crud-core/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// CompareAndSwap 重试次数用尽后仍然遇到并发修改
+var ErrCASConflict = errors.New("cache: compare-and-swap conflict")
+
+// 当缓存中的值序列化后与 expected 序列化后相同时写入 newValue，返回是否写入。
+// expected 为 nil 表示只在键不存在时写入。
+// 使用 WATCH / MULTI / EXEC 实现，读取和写入之间键被其他客户端修改时重试，
+// 超过 WithCASRetries 设置的次数后返回 ErrCASConflict
+func (rc *RedisCache) CompareAndSwap(ctx context.Context, key string, expected any, newValue any, opts ...cache.SetOption) (bool, error) {
+	options, err := setOptions(opts)
+	if err != nil {
+		return false, err
+	}
+
+	var expectedBytes []byte
+	if expected != nil {
+		b, err := rc.marshal(expected)
+		if err != nil {
+			return false, err
+		}
+		expectedBytes = b
+	}
+
+	newBytes, err := rc.encode(newValue)
+	if err != nil {
+		return false, err
+	}
+
+	cacheKey := rc.buildKey(key)
+	swapped := false
+	txf := func(tx *redis.Tx) error {
+		current, err := tx.Get(ctx, cacheKey).Bytes()
+		switch {
+		case errors.Is(err, redis.Nil):
+			if expectedBytes != nil {
+				return nil
+			}
+		case err != nil:
+			return err
+		default:
+			if expectedBytes == nil || isNegative(current) {
+				return nil
+			}
+			_, plaintext, err := rc.openEnvelope(current)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(plaintext, expectedBytes) {
+				return nil
+			}
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, cacheKey, newBytes, rc.expiration(options, newValue))
+			return nil
+		})
+		if err == nil {
+			swapped = true
+		}
+		return err
+	}
+
+	for i := 0; i <= rc.casRetries; i++ {
+		err := rc.rdb.Watch(ctx, txf, cacheKey)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return false, wrapRedisError(err)
+		}
+		return swapped, nil
+	}
+	return false, ErrCASConflict
+}
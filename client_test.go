@@ -0,0 +1,237 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUniversalOptions(t *testing.T) {
+	c, err := New(WithUniversalOptions(&redis.UniversalOptions{Addrs: []string{"localhost:7000", "localhost:7001"}}))
+	assert.Nil(t, err)
+	defer c.(*RedisCache).Close()
+	assert.NotNil(t, c.(*RedisCache).clusterClient)
+
+	c, err = New(WithUniversalOptions(&redis.UniversalOptions{Addrs: []string{"localhost:6380"}}))
+	assert.Nil(t, err)
+	defer c.(*RedisCache).Close()
+	assert.Equal(t, "localhost:6380", c.(*RedisCache).client.Options().Addr)
+}
+
+func TestUnixSocket(t *testing.T) {
+	c, err := New(WithAddr("/var/run/redis/redis.sock"))
+	assert.Nil(t, err)
+	defer c.(*RedisCache).Close()
+	assert.Equal(t, "unix", c.(*RedisCache).client.Options().Network)
+}
+
+func TestNewContextUnreachable(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	_, err := NewContext(ctx, WithAddr("localhost:1"))
+	assert.NotNil(t, err)
+}
+
+func TestRawKeys(t *testing.T) {
+	c, err := New(WithPrefix("app:"), WithRawKeys(), WithKeyBuilder(func(key string) string { return "x:" + key }))
+	assert.Nil(t, err)
+	defer c.(*RedisCache).Close()
+	assert.Equal(t, "user:1", c.(*RedisCache).buildKey("user:1"))
+}
+
+func TestLazyConnect(t *testing.T) {
+	// 默认不访问 redis
+	c, err := New(WithAddr("localhost:1"))
+	assert.Nil(t, err)
+	c.(*RedisCache).Close()
+
+	_, err = New(WithAddr("localhost:1"), WithLazyConnect(false), WithDialTimeout(100*time.Millisecond))
+	assert.NotNil(t, err)
+}
+
+func TestUnwrap(t *testing.T) {
+	c, err := New(WithPrefix("app:"), WithAddr("localhost:1"))
+	assert.Nil(t, err)
+	rc := c.(*RedisCache)
+	defer rc.Close()
+	assert.Equal(t, "app:user:1", rc.PrefixKey("user:1"))
+	assert.Equal(t, rc.rdb, rc.Unwrap())
+}
+
+func TestConnMaxLifetime(t *testing.T) {
+	c, err := New(WithAddr("localhost:1"), WithConnMaxLifetime(time.Minute), WithDialTimeout(time.Second))
+	assert.Nil(t, err)
+	rc := c.(*RedisCache)
+	defer rc.Close()
+	assert.Equal(t, time.Minute, rc.client.Options().ConnMaxLifetime)
+	assert.Equal(t, time.Second, rc.client.Options().DialTimeout)
+}
+
+func TestKeyHashing(t *testing.T) {
+	c, err := New(WithPrefix("app:"), WithKeyHashing(32))
+	assert.Nil(t, err)
+	rc := c.(*RedisCache)
+	defer rc.Close()
+
+	assert.Equal(t, "app:user:1", rc.buildKey("user:1"))
+
+	long := "https://example.com/search?q=" + strings.Repeat("a", 64)
+	sum := sha256.Sum256([]byte("app:" + long))
+	assert.Equal(t, "app:h:"+hex.EncodeToString(sum[:]), rc.buildKey(long))
+	assert.Equal(t, rc.buildKey(long), rc.PrefixKey(long))
+}
+
+// 分别以单机、集群、哨兵模式创建客户端，每次返回新的选项，避免 New 修改的配置在用例之间共享
+var topologies = []struct {
+	name string
+	opts func() []Option
+}{
+	{"client", func() []Option {
+		return []Option{WithAddr("localhost:1")}
+	}},
+	{"cluster", func() []Option {
+		return []Option{WithClusterOptions(&redis.ClusterOptions{Addrs: []string{"localhost:1"}})}
+	}},
+	{"failover", func() []Option {
+		return []Option{WithFailover(&redis.FailoverOptions{MasterName: "mymaster", SentinelAddrs: []string{"localhost:1"}})}
+	}},
+}
+
+// New 最终传给 go-redis 的连接配置中与选项相关的字段
+type builtOptions struct {
+	Username     string
+	Password     string
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	PoolSize     int
+	MinIdleConns int
+	PoolTimeout  time.Duration
+	TLSConfig    *tls.Config
+}
+
+func newBuiltOptions(t *testing.T, opts ...Option) builtOptions {
+	c, err := New(opts...)
+	assert.Nil(t, err)
+	rc := c.(*RedisCache)
+	defer rc.Close()
+
+	if rc.clusterClient != nil {
+		o := rc.clusterClient.Options()
+		return builtOptions{o.Username, o.Password, o.DialTimeout, o.ReadTimeout, o.WriteTimeout,
+			o.PoolSize, o.MinIdleConns, o.PoolTimeout, o.TLSConfig}
+	}
+	o := rc.client.Options()
+	return builtOptions{o.Username, o.Password, o.DialTimeout, o.ReadTimeout, o.WriteTimeout,
+		o.PoolSize, o.MinIdleConns, o.PoolTimeout, o.TLSConfig}
+}
+
+func TestUsername(t *testing.T) {
+	cases := []struct {
+		name     string
+		opts     []Option
+		username string
+		password string
+	}{
+		{"none", nil, "", ""},
+		{"password", []Option{WithPassword("secret")}, "", "secret"},
+		{"acl", []Option{WithUsername("app"), WithPassword("secret")}, "app", "secret"},
+	}
+	for _, topology := range topologies {
+		for _, c := range cases {
+			options := newBuiltOptions(t, append(topology.opts(), c.opts...)...)
+			assert.Equal(t, c.username, options.Username, topology.name+"/"+c.name)
+			assert.Equal(t, c.password, options.Password, topology.name+"/"+c.name)
+		}
+	}
+}
+
+func TestTimeouts(t *testing.T) {
+	cases := []struct {
+		name              string
+		opts              []Option
+		dial, read, write time.Duration
+	}{
+		{"default", nil, defaultDialTimeout, defaultReadTimeout, defaultWriteTimeout},
+		{"explicit", []Option{WithDialTimeout(time.Second), WithReadTimeout(2 * time.Second), WithWriteTimeout(4 * time.Second)},
+			time.Second, 2 * time.Second, 4 * time.Second},
+		{"partial", []Option{WithReadTimeout(500 * time.Millisecond)}, defaultDialTimeout, 500 * time.Millisecond, defaultWriteTimeout},
+	}
+	for _, topology := range topologies {
+		for _, c := range cases {
+			options := newBuiltOptions(t, append(topology.opts(), c.opts...)...)
+			assert.Equal(t, c.dial, options.DialTimeout, topology.name+"/"+c.name)
+			assert.Equal(t, c.read, options.ReadTimeout, topology.name+"/"+c.name)
+			assert.Equal(t, c.write, options.WriteTimeout, topology.name+"/"+c.name)
+		}
+	}
+
+	// WithClientOptions 中已有的超时在未设置对应选项时保留
+	options := newBuiltOptions(t, WithClientOptions(&redis.Options{Addr: "localhost:1", ReadTimeout: time.Second}))
+	assert.Equal(t, time.Second, options.ReadTimeout)
+	assert.Equal(t, defaultDialTimeout, options.DialTimeout)
+}
+
+func TestPool(t *testing.T) {
+	cases := []struct {
+		name        string
+		opts        []Option
+		size        int
+		minIdle     int
+		poolTimeout time.Duration
+	}{
+		{"explicit", []Option{WithPoolSize(20), WithMinIdleConns(5), WithPoolTimeout(time.Second)}, 20, 5, time.Second},
+		{"size only", []Option{WithPoolSize(8)}, 8, 0, 0},
+	}
+	for _, topology := range topologies {
+		for _, c := range cases {
+			options := newBuiltOptions(t, append(topology.opts(), c.opts...)...)
+			assert.Equal(t, c.size, options.PoolSize, topology.name+"/"+c.name)
+			assert.Equal(t, c.minIdle, options.MinIdleConns, topology.name+"/"+c.name)
+			// 0 表示使用 go-redis 的默认值，各类客户端填充默认值的时机不同，不做检查
+			if c.poolTimeout != 0 {
+				assert.Equal(t, c.poolTimeout, options.PoolTimeout, topology.name+"/"+c.name)
+			}
+		}
+
+		// 未设置时使用 go-redis 的默认连接池大小
+		options := newBuiltOptions(t, topology.opts()...)
+		assert.True(t, options.PoolSize > 0, topology.name)
+	}
+}
+
+func TestTLSConfig(t *testing.T) {
+	custom := &tls.Config{ServerName: "redis.internal", MinVersion: tls.VersionTLS12}
+	cases := []struct {
+		name       string
+		opts       []Option
+		tls        bool
+		serverName string
+	}{
+		{"plain", nil, false, ""},
+		{"tls", []Option{WithTLS(true)}, true, ""},
+		{"config", []Option{WithTLSConfig(custom)}, true, "redis.internal"},
+		// 显式的 tls.Config 优先于 WithTLS
+		{"config over tls", []Option{WithTLS(true), WithTLSConfig(custom)}, true, "redis.internal"},
+	}
+	for _, topology := range topologies {
+		for _, c := range cases {
+			options := newBuiltOptions(t, append(topology.opts(), c.opts...)...)
+			if !c.tls {
+				assert.Nil(t, options.TLSConfig, topology.name+"/"+c.name)
+				continue
+			}
+			if assert.NotNil(t, options.TLSConfig, topology.name+"/"+c.name) {
+				assert.Equal(t, c.serverName, options.TLSConfig.ServerName, topology.name+"/"+c.name)
+			}
+		}
+	}
+}
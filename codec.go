@@ -0,0 +1,36 @@
+package cache
+
+import "encoding/json"
+
+// 缓存值的序列化和反序列化
+type Codec interface {
+	// 将 struct 序列化为字节数组
+	Marshal(v any) ([]byte, error)
+	// 将字节数组反序列化到 v 指向的 struct
+	Unmarshal(data []byte, v any) error
+}
+
+// 基于 encoding/json 的 Codec，缓存默认使用
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// 将 MarshalFunc / UnmarshalFunc 适配为 Codec，用于兼容 WithMarshal / WithUnmarshal
+type funcCodec struct {
+	marshal   MarshalFunc
+	unmarshal UnmarshalFunc
+}
+
+func (c funcCodec) Marshal(v any) ([]byte, error) {
+	return c.marshal(v)
+}
+
+func (c funcCodec) Unmarshal(data []byte, v any) error {
+	return c.unmarshal(data, v)
+}
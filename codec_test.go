@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRawBytesAndStrings(t *testing.T) {
+	rc := &RedisCache{codec: JSONCodec{}}
+
+	html := `<div class="card">"quoted" & <b>bold</b></div>`
+	data, err := rc.encode(html)
+	assert.Nil(t, err)
+	assert.Equal(t, html, string(data))
+
+	var foundString string
+	err = rc.decode(data, &foundString)
+	assert.Nil(t, err)
+	assert.Equal(t, html, foundString)
+
+	data, err = rc.encode([]byte(html))
+	assert.Nil(t, err)
+	assert.Equal(t, html, string(data))
+
+	var foundBytes []byte
+	err = rc.decode(data, &foundBytes)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte(html), foundBytes)
+
+	// 其他类型仍然经过 codec
+	data, err = rc.encode(&User{Name: "jack"})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"name":"jack","age":0}`, string(data))
+}
+
+func TestSerializationError(t *testing.T) {
+	rc := &RedisCache{codec: JSONCodec{}}
+
+	_, err := rc.encode(make(chan int))
+	assert.ErrorIs(t, err, ErrSerialization)
+
+	var user User
+	err = rc.decode([]byte("not json"), &user)
+	assert.ErrorIs(t, err, ErrSerialization)
+	assert.NotNil(t, errors.Unwrap(err))
+}
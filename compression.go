@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+// 压缩算法，通过 WithCompressor 设置。
+// ID 写入缓存值的压缩头，用于读取时选择解压算法，切换算法后旧值仍然可以读取。
+// 开启压缩后，缓存值以 envelopeMagic 和压缩算法 ID 开头，开启压缩前写入的旧值可以据此区分。
+// ID 必须在 0x01 ~ 0x7F 之间，其中 0x01 ~ 0x0F 保留给内置算法
+type Compressor interface {
+	ID() byte
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+// gzip 压缩，WithCompression(true) 时的默认算法
+type GzipCompressor struct{}
+
+func (GzipCompressor) ID() byte {
+	return compressionGzip
+}
+
+func (GzipCompressor) Compress(data []byte) ([]byte, error) {
+	return gzipCompress(data)
+}
+
+func (GzipCompressor) Decompress(data []byte) ([]byte, error) {
+	return gzipDecompress(data)
+}
+
+// 按配置压缩 marshal 后的字节数组
+func (rc *RedisCache) compress(data []byte) ([]byte, error) {
+	if !rc.compression {
+		return data, nil
+	}
+	if len(data) < rc.compressionMin {
+		return append([]byte{envelopeMagic, compressionNone}, data...), nil
+	}
+
+	var compressor Compressor = GzipCompressor{}
+	if rc.compressor != nil {
+		compressor = rc.compressor
+	}
+	compressed, err := compressor.Compress(data)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{envelopeMagic, compressor.ID()}, compressed...), nil
+}
+
+// 识别压缩头并按标识解压，没有压缩头的值原样返回
+func (rc *RedisCache) decompress(data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != envelopeMagic {
+		return data, nil
+	}
+
+	switch id := data[1]; {
+	case id == compressionNone:
+		return data[2:], nil
+	case id == compressionGzip:
+		return gzipDecompress(data[2:])
+	case id == compressionZstd:
+		return zstdDecompress(data[2:])
+	case rc.compressor != nil && id == rc.compressor.ID():
+		return rc.compressor.Decompress(data[2:])
+	}
+	return data, nil
+}
+
+func gzipCompress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gzipDecompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
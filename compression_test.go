@@ -0,0 +1,181 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Article struct {
+	Title   string   `json:"title"`
+	Content string   `json:"content"`
+	Tags    []string `json:"tags"`
+}
+
+func newArticle() *Article {
+	return &Article{
+		Title:   "crud-cache-redis",
+		Content: strings.Repeat("为 crud-core 提供了基于 redis 的缓存实现。", 2000),
+		Tags:    []string{"redis", "cache", "go"},
+	}
+}
+
+func TestCompression(t *testing.T) {
+	plain := &RedisCache{codec: JSONCodec{}}
+	compressed := &RedisCache{codec: JSONCodec{}, compression: true}
+
+	article := newArticle()
+	plainBytes, err := plain.encode(article)
+	assert.Nil(t, err)
+	compressedBytes, err := compressed.encode(article)
+	assert.Nil(t, err)
+
+	// 重复度高的 JSON 压缩后通常只有原始大小的百分之几
+	t.Logf("plain: %d bytes, gzip: %d bytes", len(plainBytes), len(compressedBytes))
+	assert.True(t, len(compressedBytes) < len(plainBytes))
+
+	found := new(Article)
+	err = compressed.decode(compressedBytes, found)
+	assert.Nil(t, err)
+	assert.Equal(t, article, found)
+
+	// 开启压缩前写入的值仍然可以读取
+	found = new(Article)
+	err = compressed.decode(plainBytes, found)
+	assert.Nil(t, err)
+	assert.Equal(t, article, found)
+}
+
+func TestZstdCompression(t *testing.T) {
+	gzipCache := &RedisCache{codec: JSONCodec{}, compression: true}
+	zstdCache := &RedisCache{codec: JSONCodec{}, compression: true, compressor: ZstdCompressor{}}
+
+	article := newArticle()
+	zstdBytes, err := zstdCache.encode(article)
+	assert.Nil(t, err)
+	assert.Equal(t, compressionZstd, zstdBytes[1])
+
+	found := new(Article)
+	err = zstdCache.decode(zstdBytes, found)
+	assert.Nil(t, err)
+	assert.Equal(t, article, found)
+
+	// 切换算法后，之前写入的值仍然可以读取
+	gzipBytes, err := gzipCache.encode(article)
+	assert.Nil(t, err)
+	found = new(Article)
+	err = zstdCache.decode(gzipBytes, found)
+	assert.Nil(t, err)
+	assert.Equal(t, article, found)
+
+	found = new(Article)
+	err = gzipCache.decode(zstdBytes, found)
+	assert.Nil(t, err)
+	assert.Equal(t, article, found)
+}
+
+func TestCompressionThreshold(t *testing.T) {
+	rc := &RedisCache{codec: JSONCodec{}, compression: true, compressionMin: 1024}
+
+	small := &Article{Title: "small"}
+	smallBytes, err := rc.encode(small)
+	assert.Nil(t, err)
+	assert.Equal(t, compressionNone, smallBytes[1])
+
+	large := newArticle()
+	largeBytes, err := rc.encode(large)
+	assert.Nil(t, err)
+	assert.Equal(t, compressionGzip, largeBytes[1])
+
+	found := new(Article)
+	err = rc.decode(smallBytes, found)
+	assert.Nil(t, err)
+	assert.Equal(t, small, found)
+
+	found = new(Article)
+	err = rc.decode(largeBytes, found)
+	assert.Nil(t, err)
+	assert.Equal(t, large, found)
+}
+
+// 对比压缩带来的 CPU 开销，go test -bench Encode -benchmem
+// 对上面约 100 KB 的 JSON，gzip 编码耗时约为仅 json.Marshal 的 2~3 倍，体积缩小到 1% 以下；
+// 对几百字节的小对象，压缩的额外开销通常得不偿失
+func BenchmarkEncodePlain(b *testing.B) {
+	rc := &RedisCache{codec: JSONCodec{}}
+	article := newArticle()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = rc.encode(article)
+	}
+}
+
+func BenchmarkEncodeGzip(b *testing.B) {
+	rc := &RedisCache{codec: JSONCodec{}, compression: true}
+	article := newArticle()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = rc.encode(article)
+	}
+}
+
+// 同样的数据 zstd 编码通常比 gzip 更快，体积也更小
+func BenchmarkEncodeZstd(b *testing.B) {
+	rc := &RedisCache{codec: JSONCodec{}, compression: true, compressor: ZstdCompressor{}}
+	article := newArticle()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = rc.encode(article)
+	}
+}
+
+func BenchmarkDecodeGzip(b *testing.B) {
+	benchmarkDecode(b, GzipCompressor{})
+}
+
+func BenchmarkDecodeZstd(b *testing.B) {
+	benchmarkDecode(b, ZstdCompressor{})
+}
+
+func benchmarkDecode(b *testing.B, compressor Compressor) {
+	rc := &RedisCache{codec: JSONCodec{}, compression: true, compressor: compressor}
+	data, err := rc.encode(newArticle())
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportMetric(float64(len(data)), "bytes")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = rc.decode(data, new(Article))
+	}
+}
+
+func TestMaxValueSize(t *testing.T) {
+	article := newArticle()
+
+	rc := &RedisCache{codec: JSONCodec{}, maxValueSize: 1024}
+	_, err := rc.encode(article)
+	assert.ErrorIs(t, err, ErrValueTooLarge)
+
+	// 大小按压缩之后计算
+	rc = &RedisCache{codec: JSONCodec{}, maxValueSize: 1024, compression: true}
+	_, err = rc.encode(article)
+	assert.Nil(t, err)
+}
+
+type invalidCompressor struct {
+	GzipCompressor
+	id byte
+}
+
+func (c invalidCompressor) ID() byte {
+	return c.id
+}
+
+func TestCompressorID(t *testing.T) {
+	for _, id := range []byte{compressionNone, escapeEnvelope, negativeEnvelope} {
+		_, err := New(WithCompressor(invalidCompressor{id: id}), WithLazyConnect(true))
+		assert.NotNil(t, err)
+	}
+}
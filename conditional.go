@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/duolacloud/crud-core/cache"
+)
+
+// 通过 WithSetNX / WithSetXX 设置了写入条件，但条件不满足时 Set 返回的错误，表示写入被有意跳过而不是失败。
+// 不计入 Stats 的 Errors 和指标采集器的错误，也不会作为错误写入日志
+var ErrConditionFailed = errors.New("cache: set condition not met")
+
+// RedisCache 除 Set、GetOrSet、GetOrLoad 以外的写入方法传入 WithSetNX / WithSetXX 时返回的错误，
+// 这些方法不会忽略写入条件后照常写入
+var ErrConditionUnsupported = errors.New("cache: WithSetNX / WithSetXX are only supported by Set, GetOrSet and GetOrLoad")
+
+type setCondition int
+
+const (
+	setAlways setCondition = iota
+	setIfAbsent
+	setIfPresent
+)
+
+// cache.SetOptions 只有过期时间一个字段，WithSetNX / WithSetXX 将其设为以下不可能出现的负数作为标记，
+// applySetOptions 识别后恢复原来的过期时间。其它实现把负数过期时间当作未设置，标记不会生效
+const (
+	setIfAbsentMarker  = time.Duration(math.MinInt64)
+	setIfPresentMarker = time.Duration(math.MinInt64 + 1)
+)
+
+// Set 只在缓存键不存在时写入（SET ... NX），键已存在时返回 ErrConditionFailed。
+// RedisCache 的 Set、GetOrSet、GetOrLoad 以及 LRUCache.Set 支持，RedisCache 的其它写入方法返回 ErrConditionUnsupported
+func WithSetNX() cache.SetOption {
+	return func(options *cache.SetOptions) {
+		options.Exipration = setIfAbsentMarker
+	}
+}
+
+// Set 只在缓存键已存在时写入（SET ... XX），键不存在时返回 ErrConditionFailed。
+// RedisCache 的 Set、GetOrSet、GetOrLoad 以及 LRUCache.Set 支持，RedisCache 的其它写入方法返回 ErrConditionUnsupported
+func WithSetXX() cache.SetOption {
+	return func(options *cache.SetOptions) {
+		options.Exipration = setIfPresentMarker
+	}
+}
+
+// 应用写入选项，同时返回 WithSetNX / WithSetXX 设置的写入条件
+func applySetOptions(opts []cache.SetOption) (*cache.SetOptions, setCondition) {
+	options := &cache.SetOptions{}
+	condition := setAlways
+	for _, opt := range opts {
+		expiration := options.Exipration
+		opt(options)
+		switch options.Exipration {
+		case setIfAbsentMarker:
+			condition = setIfAbsent
+		case setIfPresentMarker:
+			condition = setIfPresent
+		default:
+			continue
+		}
+		options.Exipration = expiration
+	}
+	return options, condition
+}
+
+// 不支持写入条件的方法使用，设置了 WithSetNX / WithSetXX 时返回 ErrConditionUnsupported
+func setOptions(opts []cache.SetOption) (*cache.SetOptions, error) {
+	options, condition := applySetOptions(opts)
+	if condition != setAlways {
+		return nil, ErrConditionUnsupported
+	}
+	return options, nil
+}
@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/duolacloud/crud-core/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplySetOptions(t *testing.T) {
+	options, condition := applySetOptions(nil)
+	assert.Equal(t, time.Duration(0), options.Exipration)
+	assert.Equal(t, setAlways, condition)
+
+	options, condition = applySetOptions([]cache.SetOption{cache.WithExpiration(time.Second), WithSetNX()})
+	assert.Equal(t, time.Second, options.Exipration)
+	assert.Equal(t, setIfAbsent, condition)
+
+	_, condition = applySetOptions([]cache.SetOption{WithSetNX(), WithSetXX()})
+	assert.Equal(t, setIfPresent, condition)
+
+	// 写入条件不会覆盖之前设置的过期时间
+	options, condition = applySetOptions([]cache.SetOption{WithSetXX(), cache.WithExpiration(time.Second), WithSetNX()})
+	assert.Equal(t, time.Second, options.Exipration)
+	assert.Equal(t, setIfAbsent, condition)
+}
+
+func TestSetOptions(t *testing.T) {
+	options, err := setOptions([]cache.SetOption{cache.WithExpiration(time.Second)})
+	assert.Nil(t, err)
+	assert.Equal(t, time.Second, options.Exipration)
+
+	_, err = setOptions([]cache.SetOption{WithSetNX()})
+	assert.ErrorIs(t, err, ErrConditionUnsupported)
+}
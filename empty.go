@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"reflect"
+
+	"github.com/duolacloud/crud-core/types"
+)
+
+// 判断反序列化后的值是否为空，value 是调用方传给 Get 的目标。
+// 逐层解引用指针和接口后：nil 为空；切片、map 长度为 0 为空（无论是否为 nil）；
+// 其它类型（结构体、字符串、数字等）为零值时为空，结构体需要所有字段都为零值，
+// 因此只含空切片的结构体（例如 {"items":[]}）不为空
+func isEmptyValue(value any) bool {
+	v := reflect.ValueOf(value)
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return true
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// 开启 WithTreatEmptyAsMiss 且值为空时返回 types.ErrNotFound
+func (rc *RedisCache) missIfEmpty(value any) error {
+	if !rc.emptyAsMiss {
+		return nil
+	}
+
+	empty := isEmptyValue
+	if rc.emptyPredicate != nil {
+		empty = rc.emptyPredicate
+	}
+	if empty(value) {
+		return types.ErrNotFound
+	}
+	return nil
+}
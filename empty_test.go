@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/duolacloud/crud-core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsEmptyValue(t *testing.T) {
+	var nilUser *User
+	var nilSlice []User
+	emptyMap := map[string]int{}
+	var iface any = &User{}
+
+	assert.True(t, isEmptyValue(nil))
+	assert.True(t, isEmptyValue(&nilUser))
+	assert.True(t, isEmptyValue(&User{}))
+	assert.True(t, isEmptyValue(&nilSlice))
+	assert.True(t, isEmptyValue(&[]User{}))
+	assert.True(t, isEmptyValue(&emptyMap))
+	assert.True(t, isEmptyValue(new(string)))
+	assert.True(t, isEmptyValue(new(int)))
+	assert.True(t, isEmptyValue(&iface))
+
+	assert.False(t, isEmptyValue(&User{Age: 1}))
+	assert.False(t, isEmptyValue(&[]User{{}}))
+	assert.False(t, isEmptyValue(&map[string]int{"a": 0}))
+	assert.False(t, isEmptyValue(&struct{ Items []int }{Items: []int{}}))
+}
+
+func TestMissIfEmpty(t *testing.T) {
+	rc := &RedisCache{}
+	assert.Nil(t, rc.missIfEmpty(&User{}))
+
+	rc.emptyAsMiss = true
+	assert.Same(t, types.ErrNotFound, rc.missIfEmpty(&User{}))
+	assert.Nil(t, rc.missIfEmpty(&User{Name: "jack"}))
+
+	rc.emptyPredicate = func(value any) bool { return value.(*User).Name == "" }
+	assert.Same(t, types.ErrNotFound, rc.missIfEmpty(&User{Age: 18}))
+}
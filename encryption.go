@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// 开启加密后，缓存值无法用任何一个密钥解密时返回该错误
+var ErrDecryption = errors.New("cache: failed to decrypt value")
+
+func newAEADs(keys [][]byte) ([]cipher.AEAD, error) {
+	aeads := make([]cipher.AEAD, 0, len(keys))
+	for _, key := range keys {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("cache: invalid encryption key: %w", err)
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("cache: invalid encryption key: %w", err)
+		}
+		aeads = append(aeads, aead)
+	}
+	return aeads, nil
+}
+
+// 使用主密钥加密，随机 nonce 拼接在密文之前
+func (rc *RedisCache) encrypt(data []byte) ([]byte, error) {
+	if len(rc.aeads) == 0 {
+		return data, nil
+	}
+
+	aead := rc.aeads[0]
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(data)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, nil), nil
+}
+
+// 依次尝试所有密钥解密
+func (rc *RedisCache) decrypt(data []byte) ([]byte, error) {
+	if len(rc.aeads) == 0 {
+		return data, nil
+	}
+
+	for _, aead := range rc.aeads {
+		if len(data) < aead.NonceSize() {
+			continue
+		}
+
+		nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+		plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+		if err == nil {
+			return plaintext, nil
+		}
+	}
+	return nil, ErrDecryption
+}
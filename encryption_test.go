@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryption(t *testing.T) {
+	oldKey := []byte("0123456789abcdef")
+	newKey := []byte("fedcba9876543210")
+
+	oldAEADs, err := newAEADs([][]byte{oldKey})
+	assert.Nil(t, err)
+	oldCache := &RedisCache{codec: JSONCodec{}, aeads: oldAEADs}
+
+	rotatedAEADs, err := newAEADs([][]byte{newKey, oldKey})
+	assert.Nil(t, err)
+	rotatedCache := &RedisCache{codec: JSONCodec{}, aeads: rotatedAEADs}
+
+	user := &User{Name: "jack", Age: 18}
+	data, err := oldCache.encode(user)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(data), "jack")
+
+	// 轮换密钥后仍然可以解密旧密钥加密的值
+	found := new(User)
+	err = rotatedCache.decode(data, found)
+	assert.Nil(t, err)
+	assert.Equal(t, user, found)
+
+	// 新密钥加密的值无法用旧密钥解密
+	data, err = rotatedCache.encode(user)
+	assert.Nil(t, err)
+	err = oldCache.decode(data, new(User))
+	assert.ErrorIs(t, err, ErrDecryption)
+
+	_, err = newAEADs([][]byte{[]byte("short")})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,74 @@
+package cache
+
+import "time"
+
+// 缓存值的封装头以 envelopeMagic 和一个字节的标签开头，0xC1 在 JSON、msgpack 中都不会作为首字节出现。
+// 所有标签统一在这里分配，新增封装头时需要在此登记：
+//
+//	0x00        未压缩，开启压缩但值小于压缩阈值时使用
+//	0x01 ~ 0x7F 压缩算法 ID，0x01 ~ 0x0F 保留给内置算法
+//	0xFB        转义，[]byte、string 等 marshal 结果或密文本身以 envelopeMagic 开头时加上，读取时原样去掉
+//	0xFC        结构版本号，见 WithSchemaVersion
+//	0xFD        版本号，见 SetIfNewer
+//	0xFE        软过期时间，见 GetStale
+//	0xFF        未命中记录，见 SetMiss
+//
+// 写入时由内向外依次为转义、结构版本号、压缩、加密及其转义，最外层为软过期时间或版本号，openEnvelope 按相反的顺序解析。
+// 每一层写入的字节数组要么不以 envelopeMagic 开头，要么以本层的标签开头，因此原始值不会被误认为封装头
+const (
+	envelopeMagic byte = 0xC1
+
+	compressionNone byte = 0x00
+	compressionGzip byte = 0x01
+	compressionZstd byte = 0x02
+	maxCompressorID byte = 0x7F
+
+	escapeEnvelope   byte = 0xFB
+	schemaEnvelope   byte = 0xFC
+	versionEnvelope  byte = 0xFD
+	staleEnvelope    byte = 0xFE
+	negativeEnvelope byte = 0xFF
+)
+
+// data 是否以 tag 对应的封装头开头，且长度不少于 size 字节
+func hasEnvelope(data []byte, tag byte, size int) bool {
+	return len(data) >= size && len(data) >= 2 && data[0] == envelopeMagic && data[1] == tag
+}
+
+// 依次去掉版本号、软过期时间，解密、解压并检查结构版本号，返回软过期时间和 marshal 得到的字节数组。
+// 未命中记录是完整的值而不是封装头，由调用方在此之前通过 isNegative 判断
+func (rc *RedisCache) openEnvelope(data []byte) (time.Time, []byte, error) {
+	data = stripVersion(data)
+	softExpireAt, data := parseStale(data)
+	if len(rc.aeads) > 0 {
+		data = unescape(data)
+	}
+	data, err := rc.decrypt(data)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	data, err = rc.decompress(data)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	data, err = rc.checkSchemaVersion(data)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	return softExpireAt, unescape(data), nil
+}
+
+// 以 envelopeMagic 开头的字节数组加上转义头，避免被读取时当作其它封装头解析
+func escape(data []byte) []byte {
+	if len(data) == 0 || data[0] != envelopeMagic {
+		return data
+	}
+	return append([]byte{envelopeMagic, escapeEnvelope}, data...)
+}
+
+func unescape(data []byte) []byte {
+	if !hasEnvelope(data, escapeEnvelope, 2) {
+		return data
+	}
+	return data[2:]
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenEnvelope(t *testing.T) {
+	aeads, err := newAEADs([][]byte{bytes.Repeat([]byte{1}, 32)})
+	assert.Nil(t, err)
+	rc := &RedisCache{codec: JSONCodec{}, compression: true, schemaVersion: 3, aeads: aeads}
+
+	data, err := rc.encode(&User{Name: "jack"})
+	assert.Nil(t, err)
+
+	// 最外层加上 GetStale 的软过期时间
+	stale := make([]byte, staleHeaderBytes, staleHeaderBytes+len(data))
+	stale[0], stale[1] = envelopeMagic, staleEnvelope
+	stale[9] = 1
+	stale = append(stale, data...)
+
+	softExpireAt, plaintext, err := rc.openEnvelope(stale)
+	assert.Nil(t, err)
+	assert.Equal(t, time.UnixMilli(1), softExpireAt)
+	assert.Equal(t, `{"name":"jack","age":0}`, string(plaintext))
+}
+
+func TestHasEnvelope(t *testing.T) {
+	assert.True(t, hasEnvelope(negativeValue, negativeEnvelope, 2))
+	assert.False(t, hasEnvelope(negativeValue, staleEnvelope, 2))
+	assert.False(t, hasEnvelope([]byte{envelopeMagic, staleEnvelope}, staleEnvelope, staleHeaderBytes))
+	assert.False(t, hasEnvelope([]byte{envelopeMagic}, compressionNone, 1))
+}
+
+func TestRawValueLooksLikeEnvelope(t *testing.T) {
+	cases := [][]byte{
+		{envelopeMagic, schemaEnvelope, 0x01, 'a', 'b'},
+		{envelopeMagic, negativeEnvelope},
+		{envelopeMagic, compressionGzip, 'a', 'b'},
+		{envelopeMagic, escapeEnvelope, 'a'},
+		{envelopeMagic, staleEnvelope, 0, 0, 0, 0, 0, 0, 0, 1, 'a'},
+	}
+	aeads, err := newAEADs([][]byte{bytes.Repeat([]byte{1}, 32)})
+	assert.Nil(t, err)
+	caches := []*RedisCache{
+		{codec: JSONCodec{}},
+		{codec: JSONCodec{}, compression: true},
+		{codec: JSONCodec{}, compression: true, compressionMin: 1024},
+		{codec: JSONCodec{}, schemaVersion: 1},
+		{codec: JSONCodec{}, aeads: aeads},
+	}
+	for _, rc := range caches {
+		for _, raw := range cases {
+			data, err := rc.encode(raw)
+			assert.Nil(t, err)
+			assert.False(t, isNegative(data))
+
+			var found []byte
+			err = rc.decode(data, &found)
+			assert.Nil(t, err)
+			assert.Equal(t, raw, found)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	assert.Equal(t, []byte("ab"), escape([]byte("ab")))
+	assert.Equal(t, []byte{envelopeMagic, escapeEnvelope, envelopeMagic}, escape([]byte{envelopeMagic}))
+	assert.Equal(t, []byte{envelopeMagic}, unescape(escape([]byte{envelopeMagic})))
+	assert.Equal(t, []byte{}, escape([]byte{}))
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/duolacloud/crud-core/types"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// 无法连接 redis 或连接中断，包括拨号失败、连接被重置和连接已关闭
+	ErrConnection = errors.New("cache: redis connection failed")
+	// 操作超时，包括 ctx 超时和网络读写超时
+	ErrTimeout = errors.New("cache: operation timed out")
+	// redis 返回了错误回复，例如 WRONGTYPE、NOSCRIPT、OOM
+	ErrServer = errors.New("cache: redis server error")
+	// 操作被 ctx 取消
+	ErrCanceled = errors.New("cache: operation canceled")
+	// 写入的值超过 WithMaxValueSize 设置的大小
+	ErrValueTooLarge = errors.New("cache: value too large")
+	// 熔断器打开，请求未发往 redis
+	ErrCircuitOpen = errors.New("cache: circuit breaker is open")
+	// redis 未加载 RedisJSON 模块
+	ErrRedisJSONUnavailable = errors.New("cache: RedisJSON module is not loaded")
+	// 缓存没有前缀时拒绝执行 Clear
+	ErrEmptyPrefix = errors.New("cache: refusing to clear without a prefix")
+	// 缓存值序列化或反序列化失败，errors.Unwrap 可以得到 codec 返回的原始错误
+	ErrSerialization = errors.New("cache: serialization failed")
+	// Get 的目标不是非 nil 的指针
+	ErrInvalidDestination = errors.New("cache: dest must be a non-nil pointer")
+)
+
+// Get 反序列化前检查目标，避免传入值而不是指针时得到 codec 难以理解的错误
+func checkDestination(value any) error {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return ErrInvalidDestination
+	}
+	return nil
+}
+
+// 将原始错误归类到包导出的错误，errors.Is 对归类后的错误和原始错误都成立
+type classifiedError struct {
+	kind error
+	err  error
+}
+
+func (e *classifiedError) Error() string {
+	return e.kind.Error() + ": " + e.err.Error()
+}
+
+func (e *classifiedError) Is(target error) bool {
+	return target == e.kind
+}
+
+func (e *classifiedError) Unwrap() error {
+	return e.err
+}
+
+// 将 redis 错误适配为 crud-core 错误和本包导出的错误：未命中为 types.ErrNotFound，
+// 其它错误归类为 ErrConnection、ErrTimeout、ErrCanceled 或 ErrServer，并保留原始错误
+func wrapRedisError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, redis.Nil) {
+		return types.ErrNotFound
+	}
+	// 已经归类过的错误不再重复包装
+	var classified *classifiedError
+	if errors.As(err, &classified) {
+		return err
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &classifiedError{kind: ErrTimeout, err: err}
+	}
+	if errors.Is(err, context.Canceled) {
+		return &classifiedError{kind: ErrCanceled, err: err}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return &classifiedError{kind: ErrTimeout, err: err}
+	}
+	if errors.Is(err, redis.ErrClosed) || isRetryable(err) {
+		return &classifiedError{kind: ErrConnection, err: err}
+	}
+
+	var serverErr redis.Error
+	if errors.As(err, &serverErr) {
+		return &classifiedError{kind: ErrServer, err: err}
+	}
+	return err
+}
+
+// MGet 个别值反序列化失败时返回的错误，Errors 的键是失败的键在 Keys 中的下标
+type MultiError struct {
+	Keys   []string
+	Errors map[int]error
+}
+
+func (e *MultiError) Error() string {
+	indexes := e.indexes()
+	msgs := make([]string, 0, len(indexes))
+	for _, i := range indexes {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", e.Keys[i], e.Errors[i]))
+	}
+	return fmt.Sprintf("cache: %d of %d keys failed: %s", len(indexes), len(e.Keys), strings.Join(msgs, "; "))
+}
+
+// 按下标顺序返回所有错误，Go 1.20 起 errors.Is / errors.As 会逐个检查
+func (e *MultiError) Unwrap() []error {
+	indexes := e.indexes()
+	errs := make([]error, 0, len(indexes))
+	for _, i := range indexes {
+		errs = append(errs, e.Errors[i])
+	}
+	return errs
+}
+
+func (e *MultiError) indexes() []int {
+	indexes := make([]int, 0, len(e.Errors))
+	for i := range e.Errors {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/duolacloud/crud-core/types"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapRedisError(t *testing.T) {
+	assert.Nil(t, wrapRedisError(nil))
+	assert.Same(t, types.ErrNotFound, wrapRedisError(redis.Nil))
+
+	err := wrapRedisError(context.DeadlineExceeded)
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	err = wrapRedisError(context.Canceled)
+	assert.ErrorIs(t, err, ErrCanceled)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	err = wrapRedisError(io.EOF)
+	assert.ErrorIs(t, err, ErrConnection)
+	assert.ErrorIs(t, err, io.EOF)
+
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	err = wrapRedisError(dialErr)
+	assert.ErrorIs(t, err, ErrConnection)
+	assert.ErrorIs(t, wrapRedisError(redis.ErrClosed), ErrConnection)
+
+	serverErr := redisError("WRONGTYPE Operation against a key holding the wrong kind of value")
+	err = wrapRedisError(serverErr)
+	assert.ErrorIs(t, err, ErrServer)
+	assert.ErrorIs(t, err, serverErr)
+	assert.False(t, errors.Is(err, ErrConnection))
+
+	// 重复包装不改变错误
+	assert.Same(t, err, wrapRedisError(err))
+
+	other := errors.New("ERR unknown command")
+	assert.Same(t, other, wrapRedisError(other))
+}
+
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+func (redisError) RedisError() {}
+
+func TestWrapJSONError(t *testing.T) {
+	err := wrapJSONError(errors.New("ERR unknown command 'JSON.SET', with args beginning with: "))
+	assert.ErrorIs(t, err, ErrRedisJSONUnavailable)
+	assert.Same(t, types.ErrNotFound, wrapJSONError(redis.Nil))
+}
+
+func TestMultiError(t *testing.T) {
+	err := &MultiError{
+		Keys:   []string{"a", "b", "c"},
+		Errors: map[int]error{2: ErrSerialization, 0: io.EOF},
+	}
+	assert.Equal(t, "cache: 2 of 3 keys failed: a: EOF; c: cache: serialization failed", err.Error())
+	assert.Equal(t, []error{io.EOF, ErrSerialization}, err.Unwrap())
+}
+
+func TestInvalidDestination(t *testing.T) {
+	var nilUser *User
+	assert.Nil(t, checkDestination(&User{}))
+	assert.ErrorIs(t, checkDestination(User{}), ErrInvalidDestination)
+	assert.ErrorIs(t, checkDestination(nil), ErrInvalidDestination)
+	assert.ErrorIs(t, checkDestination(nilUser), ErrInvalidDestination)
+
+	// 不访问 redis
+	c, err := New(WithAddr("localhost:1"))
+	assert.Nil(t, err)
+	defer c.(*RedisCache).Close()
+	err = c.Get(context.TODO(), "user:1", User{})
+	assert.Same(t, ErrInvalidDestination, err)
+
+	err = NewInMemory().Get(context.TODO(), "user:1", User{})
+	assert.Same(t, ErrInvalidDestination, err)
+}
@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/duolacloud/crud-core/cache"
+)
+
+// 根据缓存值计算过期时间，返回 0 表示不过期
+type TTLFunc func(value any) time.Duration
+
+// 缓存值可以实现该接口自行决定过期时间，返回 0 或负数表示不过期
+type Expirer interface {
+	CacheTTL() time.Duration
+}
+
+// 计算写入 redis 时实际使用的过期时间，0 表示不过期。
+// 优先级依次为：调用方显式设置的过期时间、值实现的 Expirer、WithTTLFunc、WithDefaultExpiration。
+// 显式设置 NoExpiration 时不过期
+func (rc *RedisCache) expiration(options *cache.SetOptions, value any) time.Duration {
+	d := options.Exipration
+	if d == 0 {
+		if expirer, ok := value.(Expirer); ok {
+			if d = expirer.CacheTTL(); d <= 0 {
+				return 0
+			}
+		}
+	}
+	if d == 0 && rc.ttlFunc != nil {
+		d = rc.ttlFunc(value)
+	}
+	if d == 0 {
+		d = rc.defaultTTL
+	}
+	if d < 0 {
+		return 0
+	}
+	return rc.jitter(d)
+}
+
+// 在 [d*(1-fraction), d*(1+fraction)] 范围内随机调整过期时间，d 为 0 时不调整
+func (rc *RedisCache) jitter(d time.Duration) time.Duration {
+	if d <= 0 || rc.jitterFraction <= 0 {
+		return d
+	}
+
+	delta := time.Duration((rand.Float64()*2 - 1) * rc.jitterFraction * float64(d))
+	if jittered := d + delta; jittered >= time.Millisecond {
+		return jittered
+	}
+	return time.Millisecond
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/duolacloud/crud-core/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpirationJitter(t *testing.T) {
+	rc := &RedisCache{jitterFraction: 0.1}
+
+	for i := 0; i < 100; i++ {
+		d := rc.expiration(&cache.SetOptions{Exipration: 10 * time.Second}, nil)
+		assert.True(t, d >= 9*time.Second && d <= 11*time.Second)
+	}
+
+	assert.Equal(t, time.Duration(0), rc.expiration(&cache.SetOptions{}, nil))
+
+	rc = &RedisCache{}
+	assert.Equal(t, 10*time.Second, rc.expiration(&cache.SetOptions{Exipration: 10 * time.Second}, nil))
+}
+
+func TestTTLFunc(t *testing.T) {
+	rc := &RedisCache{ttlFunc: func(value any) time.Duration {
+		return time.Duration(value.(int)) * time.Second
+	}}
+
+	assert.Equal(t, 3*time.Second, rc.expiration(&cache.SetOptions{}, 3))
+	// 显式设置的过期时间优先
+	assert.Equal(t, 10*time.Second, rc.expiration(&cache.SetOptions{Exipration: 10 * time.Second}, 3))
+}
+
+func TestDefaultExpiration(t *testing.T) {
+	rc := &RedisCache{defaultTTL: time.Minute}
+
+	assert.Equal(t, time.Minute, rc.expiration(&cache.SetOptions{}, nil))
+	assert.Equal(t, 10*time.Second, rc.expiration(&cache.SetOptions{Exipration: 10 * time.Second}, nil))
+	assert.Equal(t, time.Duration(0), rc.expiration(&cache.SetOptions{Exipration: NoExpiration}, nil))
+}
+
+type token struct {
+	expiresAt time.Time
+}
+
+func (t *token) CacheTTL() time.Duration {
+	return time.Until(t.expiresAt)
+}
+
+func TestExpirer(t *testing.T) {
+	rc := &RedisCache{defaultTTL: time.Hour}
+
+	d := rc.expiration(&cache.SetOptions{}, &token{expiresAt: time.Now().Add(time.Minute)})
+	assert.True(t, d > 59*time.Second && d <= time.Minute)
+
+	// 已过期时不设置过期时间，也不使用默认过期时间
+	assert.Equal(t, time.Duration(0), rc.expiration(&cache.SetOptions{}, &token{expiresAt: time.Now().Add(-time.Minute)}))
+
+	// 显式设置的过期时间优先
+	assert.Equal(t, time.Second, rc.expiration(&cache.SetOptions{Exipration: time.Second}, &token{expiresAt: time.Now().Add(time.Minute)}))
+}
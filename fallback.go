@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/duolacloud/crud-core/cache"
+)
+
+// redis 出现连接层面的错误且备用缓存也失败时返回，
+// errors.Is 对两层的错误都成立，Primary 为 redis 的错误，Fallback 为备用缓存的错误
+type FallbackError struct {
+	Primary  error
+	Fallback error
+}
+
+func (e *FallbackError) Error() string {
+	return "cache: redis failed: " + e.Primary.Error() + "; fallback failed: " + e.Fallback.Error()
+}
+
+func (e *FallbackError) Is(target error) bool {
+	return errors.Is(e.Primary, target)
+}
+
+func (e *FallbackError) Unwrap() error {
+	return e.Fallback
+}
+
+// 判断是否为 redis 不可用导致的错误，未命中、编码等逻辑错误不切换到备用缓存
+func isConnectionError(err error) bool {
+	return errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrTimeout) || errors.Is(err, ErrConnection) || isRetryable(err)
+}
+
+// 需要切换到备用缓存时返回 true
+func (rc *RedisCache) useFallback(err error) bool {
+	return rc.fallback != nil && err != nil && isConnectionError(err)
+}
+
+// 在 redis 不可用时从备用缓存读取，备用缓存命中时返回 nil
+func (rc *RedisCache) getFallback(ctx context.Context, key string, value any, primary error, opts []cache.GetOption) error {
+	if err := rc.fallback.Get(ctx, key, value, opts...); err != nil {
+		return &FallbackError{Primary: primary, Fallback: err}
+	}
+	return nil
+}
+
+// 将写入同步到备用缓存。redis 不可用时以备用缓存的结果为准，否则返回 redis 的结果。
+// 与读取相同，只在 redis 写入成功或连接层面出错时同步，其它错误时 redis 中的值没有变化，同步会使两者不一致
+func (rc *RedisCache) mirror(primary error, write func() error) error {
+	if rc.fallback == nil || !rc.fallbackWrites {
+		return primary
+	}
+	if primary != nil && !isConnectionError(primary) {
+		return primary
+	}
+
+	err := write()
+	if !rc.useFallback(primary) {
+		return primary
+	}
+	if err != nil {
+		return &FallbackError{Primary: primary, Fallback: err}
+	}
+	return nil
+}
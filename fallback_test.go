@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/duolacloud/crud-core/cache"
+	"github.com/duolacloud/crud-core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFallback(t *testing.T) {
+	fallback := NewLRU(10, time.Minute)
+	// 没有 redis 监听的端口，所有命令都会连接失败
+	c, err := New(WithAddr("localhost:1"), WithFallback(fallback), WithFallbackWrites())
+	assert.Nil(t, err)
+	rc := c.(*RedisCache)
+	defer rc.Close()
+
+	err = rc.Set(context.TODO(), "fallback", &User{Name: "jack"})
+	assert.Nil(t, err)
+
+	var user User
+	err = rc.Get(context.TODO(), "fallback", &user)
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", user.Name)
+
+	err = rc.Delete(context.TODO(), "fallback")
+	assert.Nil(t, err)
+
+	err = rc.Get(context.TODO(), "fallback", &user)
+	var fallbackErr *FallbackError
+	assert.ErrorAs(t, err, &fallbackErr)
+	assert.ErrorIs(t, err, types.ErrNotFound)
+	assert.NotNil(t, fallbackErr.Primary)
+}
+
+// 记录收到的写入选项，模拟不认识 WithSetNX / WithSetXX 的备用缓存
+type recordingCache struct {
+	cache.Cache
+	expirations []time.Duration
+}
+
+func (c *recordingCache) Set(ctx context.Context, key string, value any, opts ...cache.SetOption) error {
+	options := &cache.SetOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	c.expirations = append(c.expirations, options.Exipration)
+	return nil
+}
+
+func TestFallbackConditionalSet(t *testing.T) {
+	fallback := &recordingCache{Cache: NewInMemory()}
+	c, err := New(WithPrefix("curd-cache-redis:"), WithFallback(fallback), WithFallbackWrites())
+	assert.Nil(t, err)
+	rc := c.(*RedisCache)
+	defer rc.Close()
+
+	err = rc.Delete(context.TODO(), "fallback_nx")
+	assert.Nil(t, err)
+	err = rc.Set(context.TODO(), "fallback_nx", &User{Name: "jack"}, cache.WithExpiration(5*time.Second), WithSetNX())
+	assert.Nil(t, err)
+	assert.Equal(t, []time.Duration{5 * time.Second}, fallback.expirations)
+
+	// redis 不可用时条件写入不会落到备用缓存
+	down, err := New(WithAddr("localhost:1"), WithFallback(fallback), WithFallbackWrites())
+	assert.Nil(t, err)
+	defer down.(*RedisCache).Close()
+	err = down.Set(context.TODO(), "fallback_nx", &User{Name: "jack"}, WithSetNX())
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, len(fallback.expirations))
+}
+
+func TestMirror(t *testing.T) {
+	rc := &RedisCache{fallback: NewInMemory(), fallbackWrites: true}
+	writes := 0
+	write := func() error {
+		writes++
+		return nil
+	}
+
+	assert.Nil(t, rc.mirror(nil, write))
+	assert.Equal(t, 1, writes)
+
+	// 连接错误以备用缓存的结果为准
+	assert.Nil(t, rc.mirror(ErrConnection, write))
+	assert.Equal(t, 2, writes)
+
+	// 其它错误不同步
+	serverErr := errors.New("ERR max number of clients reached")
+	assert.Same(t, serverErr, rc.mirror(serverErr, write))
+	assert.Equal(t, 2, writes)
+}
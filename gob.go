@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+// 基于 gob 的序列化函数，可以直接传给 WithMarshal，
+// 可以保留 interface 字段的具体类型，interface 中保存的类型需要先通过 GobRegister 注册
+func GobMarshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// 基于 gob 的反序列化函数，可以直接传给 WithUnmarshal
+func GobUnmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// 注册 interface 字段中可能出现的具体类型，等同于对每个值调用 gob.Register
+func GobRegister(values ...any) {
+	for _, value := range values {
+		gob.Register(value)
+	}
+}
+
+// 基于 gob 的 Codec，可以直接传给 WithCodec
+type GobCodec struct{}
+
+func (GobCodec) Marshal(v any) ([]byte, error) {
+	return GobMarshal(v)
+}
+
+func (GobCodec) Unmarshal(data []byte, v any) error {
+	return GobUnmarshal(data, v)
+}
+
+// 同 GobRegister
+func (GobCodec) Register(values ...any) {
+	GobRegister(values...)
+}
@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Shape interface {
+	Area() float64
+}
+
+type Square struct {
+	Side float64
+}
+
+func (s Square) Area() float64 {
+	return s.Side * s.Side
+}
+
+type Drawing struct {
+	Name    string
+	Timeout time.Duration
+	Shape   Shape
+}
+
+func TestGob(t *testing.T) {
+	codec := GobCodec{}
+	codec.Register(Square{})
+
+	drawing := &Drawing{Name: "square", Timeout: 3 * time.Second, Shape: Square{Side: 2}}
+
+	data, err := codec.Marshal(drawing)
+	assert.Nil(t, err)
+
+	found := new(Drawing)
+	err = codec.Unmarshal(data, found)
+	assert.Nil(t, err)
+	assert.Equal(t, drawing, found)
+	assert.Equal(t, 4.0, found.Shape.Area())
+
+	// JSON 无法还原 interface 字段的具体类型
+	data, err = json.Marshal(drawing)
+	assert.Nil(t, err)
+	err = json.Unmarshal(data, new(Drawing))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/duolacloud/crud-core/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// 使用 HSET 写入哈希的多个字段，每个字段的值单独序列化，未列出的字段保持不变。
+// 设置了过期时间时刷新整个哈希键的过期时间，未设置时保留键原有的过期时间
+func (rc *RedisCache) SetFields(ctx context.Context, key string, fields map[string]any, opts ...cache.SetOption) error {
+	options, err := setOptions(opts)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	values := make([]any, 0, len(fields)*2)
+	for field, value := range fields {
+		bytes, err := rc.encode(value)
+		if err != nil {
+			return err
+		}
+		values = append(values, field, bytes)
+	}
+
+	cacheKey := rc.buildKey(key)
+	expiration := rc.expiration(options, fields)
+	_, err = rc.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, cacheKey, values...)
+		if expiration > 0 {
+			pipe.PExpire(ctx, cacheKey, expiration)
+		}
+		return nil
+	})
+	return wrapRedisError(err)
+}
+
+// 使用 HGET 读取哈希的单个字段，键或字段不存在时返回 types.ErrNotFound
+func (rc *RedisCache) GetField(ctx context.Context, key string, field string, dest any) error {
+	bytes, err := rc.rdb.HGet(ctx, rc.buildKey(key), field).Bytes()
+	if err != nil {
+		return wrapRedisError(err)
+	}
+	return rc.decode(bytes, dest)
+}
+
+// 使用 HGETALL 读取哈希的所有字段，dest 必须是指向 map[string]T 的指针，
+// 每个字段反序列化为 T，结构版本号不同的字段会被跳过。键不存在时返回 types.ErrNotFound
+func (rc *RedisCache) GetFields(ctx context.Context, key string, dest any) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Map || rv.Elem().Type().Key().Kind() != reflect.String {
+		return errors.New("cache: GetFields dest must be a non-nil pointer to a map with string keys")
+	}
+
+	values, err := rc.rdb.HGetAll(ctx, rc.buildKey(key)).Result()
+	if err != nil {
+		return wrapRedisError(err)
+	}
+	if len(values) == 0 {
+		return wrapRedisError(redis.Nil)
+	}
+
+	mapType := rv.Elem().Type()
+	m := reflect.MakeMapWithSize(mapType, len(values))
+	for field, value := range values {
+		elem := reflect.New(mapType.Elem())
+		if err := rc.decode([]byte(value), elem.Interface()); err != nil {
+			if errors.Is(err, ErrSchemaMismatch) {
+				continue
+			}
+			return err
+		}
+		m.SetMapIndex(reflect.ValueOf(field).Convert(mapType.Key()), elem.Elem())
+	}
+	rv.Elem().Set(m)
+	return nil
+}
+
+// 将一个小值写入分组 group 的字段 field，同一分组的所有字段保存在同一个哈希键中（HSET），
+// 共享一个键的开销和一个过期时间，适合大量很小的值，例如每个用户的开关。
+// 代价是无法为单个字段设置过期时间：ttl 大于 0 时刷新整个分组的过期时间，
+// 为 0 时保留分组原有的过期时间，为 NoExpiration 时分组不再过期
+func (rc *RedisCache) GroupSet(ctx context.Context, group string, field string, value any, ttl time.Duration) error {
+	bytes, err := rc.encode(value)
+	if err != nil {
+		return err
+	}
+
+	cacheKey := rc.buildKey(group)
+	_, err = rc.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, cacheKey, field, bytes)
+		switch {
+		case ttl > 0:
+			pipe.PExpire(ctx, cacheKey, ttl)
+		case ttl < 0:
+			pipe.Persist(ctx, cacheKey)
+		}
+		return nil
+	})
+	return wrapRedisError(err)
+}
+
+// 读取 GroupSet 写入的字段（HGET），分组或字段不存在时返回 types.ErrNotFound
+func (rc *RedisCache) GroupGet(ctx context.Context, group string, field string, dest any) error {
+	return rc.GetField(ctx, group, field, dest)
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/duolacloud/crud-core/types"
+)
+
+// 操作钩子，通过 WithHook 注册，key 为加上前缀后的 redis 键。
+// Before 返回的 ctx 会用于本次操作和对应的 After 调用，err 为操作最终返回的错误
+type Hook interface {
+	BeforeGet(ctx context.Context, key string) context.Context
+	AfterGet(ctx context.Context, key string, d time.Duration, err error)
+	BeforeSet(ctx context.Context, key string) context.Context
+	AfterSet(ctx context.Context, key string, d time.Duration, err error)
+	BeforeDelete(ctx context.Context, key string) context.Context
+	AfterDelete(ctx context.Context, key string, d time.Duration, err error)
+}
+
+// 钩子可以额外实现的接口，Get 读取到值、Set 完成编码后调用，size 为 redis 中保存的字节数，
+// ctx 为 Before 返回的 ctx
+type ValueSizeHook interface {
+	ObserveValueSize(ctx context.Context, key string, size int)
+}
+
+// 按注册顺序调用所有钩子的 Before 方法
+func (rc *RedisCache) beforeHooks(ctx context.Context, op string, cacheKey string) context.Context {
+	for _, hook := range rc.hooks {
+		switch op {
+		case opGet:
+			ctx = hook.BeforeGet(ctx, cacheKey)
+		case opSet:
+			ctx = hook.BeforeSet(ctx, cacheKey)
+		case opDelete:
+			ctx = hook.BeforeDelete(ctx, cacheKey)
+		}
+	}
+	return ctx
+}
+
+// 按注册顺序调用所有钩子的 After 方法
+func (rc *RedisCache) afterHooks(ctx context.Context, op string, cacheKey string, start time.Time, errp *error) {
+	d := time.Since(start)
+	for _, hook := range rc.hooks {
+		switch op {
+		case opGet:
+			hook.AfterGet(ctx, cacheKey, d, *errp)
+		case opSet:
+			hook.AfterSet(ctx, cacheKey, d, *errp)
+		case opDelete:
+			hook.AfterDelete(ctx, cacheKey, d, *errp)
+		}
+	}
+}
+
+// 调用实现了 ValueSizeHook 的钩子
+func (rc *RedisCache) valueSizeHooks(ctx context.Context, cacheKey string, size int) {
+	for _, hook := range rc.hooks {
+		if h, ok := hook.(ValueSizeHook); ok {
+			h.ObserveValueSize(ctx, cacheKey, size)
+		}
+	}
+}
+
+// Get 读取到的值无法还原时调用，key 为调用方传入的键，raw 为 redis 中保存的原始字节，
+// err 为解密、解压或反序列化的错误。返回 true 时 Get 当作未命中返回 types.ErrNotFound，
+// GetOrSet / GetOrLoad 会重新加载；返回 false 时 Get 返回 err
+type DecodeErrorFunc func(key string, raw []byte, err error) (miss bool)
+
+// 记录 Get 的反序列化失败并调用 WithOnDecodeError 设置的回调，结构版本号不同的值已经是未命中，不会触发
+func (rc *RedisCache) decodeFailed(key string, raw []byte, err error) error {
+	if errors.Is(err, ErrSchemaMismatch) {
+		return err
+	}
+	if rc.metrics != nil {
+		rc.metrics.ObserveError(opDecode)
+	}
+	if rc.onDecodeError != nil && rc.onDecodeError(key, raw, err) {
+		return types.ErrNotFound
+	}
+	return err
+}
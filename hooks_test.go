@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/duolacloud/crud-core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordHook struct {
+	name  string
+	calls *[]string
+}
+
+func (h recordHook) record(call string) { *h.calls = append(*h.calls, h.name+"."+call) }
+
+func (h recordHook) BeforeGet(ctx context.Context, key string) context.Context {
+	h.record("BeforeGet")
+	return ctx
+}
+func (h recordHook) AfterGet(ctx context.Context, key string, d time.Duration, err error) {
+	h.record("AfterGet")
+}
+func (h recordHook) BeforeSet(ctx context.Context, key string) context.Context {
+	h.record("BeforeSet")
+	return ctx
+}
+func (h recordHook) AfterSet(ctx context.Context, key string, d time.Duration, err error) {
+	h.record("AfterSet")
+}
+func (h recordHook) BeforeDelete(ctx context.Context, key string) context.Context {
+	h.record("BeforeDelete")
+	return ctx
+}
+func (h recordHook) AfterDelete(ctx context.Context, key string, d time.Duration, err error) {
+	h.record("AfterDelete")
+}
+
+func TestHooksOrder(t *testing.T) {
+	var calls []string
+	rc := &RedisCache{}
+	WithHook(recordHook{"a", &calls})(rc)
+	WithHook(recordHook{"b", &calls})(rc)
+
+	ctx := rc.beforeHooks(context.Background(), opGet, "k")
+	var err error
+	rc.afterHooks(ctx, opGet, "k", time.Now(), &err)
+
+	assert.Equal(t, []string{"a.BeforeGet", "b.BeforeGet", "a.AfterGet", "b.AfterGet"}, calls)
+}
+
+func TestDecodeFailed(t *testing.T) {
+	collector := &testCollector{}
+	var gotKey string
+	var gotRaw []byte
+	rc := &RedisCache{metrics: collector}
+	rc.onDecodeError = func(key string, raw []byte, err error) bool {
+		gotKey, gotRaw = key, raw
+		return errors.Is(err, ErrSerialization)
+	}
+
+	decodeErr := &classifiedError{kind: ErrSerialization, err: errors.New("bad json")}
+	err := rc.decodeFailed("user:1", []byte("not json"), decodeErr)
+	assert.Same(t, types.ErrNotFound, err)
+	assert.Equal(t, "user:1", gotKey)
+	assert.Equal(t, []byte("not json"), gotRaw)
+
+	other := errors.New("cipher: message authentication failed")
+	assert.Same(t, other, rc.decodeFailed("user:2", nil, other))
+	assert.Equal(t, []string{opDecode, opDecode}, collector.errors)
+
+	// 结构版本号不同不算反序列化失败
+	gotKey = ""
+	err = rc.decodeFailed("user:3", nil, &classifiedError{kind: ErrSchemaMismatch, err: types.ErrNotFound})
+	assert.ErrorIs(t, err, ErrSchemaMismatch)
+	assert.Equal(t, "", gotKey)
+	assert.Len(t, collector.errors, 2)
+}
+
+type sizeHook struct {
+	recordHook
+	sizes map[string]int
+}
+
+func (h sizeHook) ObserveValueSize(ctx context.Context, key string, size int) {
+	h.sizes[key] = size
+}
+
+func TestValueSizeHooks(t *testing.T) {
+	var calls []string
+	hook := sizeHook{recordHook{"a", &calls}, make(map[string]int)}
+	rc := &RedisCache{}
+	WithHook(recordHook{"b", &calls})(rc)
+	WithHook(hook)(rc)
+
+	rc.valueSizeHooks(context.Background(), "k", 12)
+	assert.Equal(t, map[string]int{"k": 12}, hook.sizes)
+	assert.Empty(t, calls)
+}
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+const (
+	// 未设置 WithAutoInvalidation 时使用的失效广播频道
+	defaultInvalidationChannel = "crud-cache:invalidate"
+	// 订阅连接断开后重新订阅前的等待时间
+	invalidationReconnectDelay = time.Second
+)
+
+func (rc *RedisCache) invalidationChannelName() string {
+	if len(rc.invalidation) > 0 {
+		return rc.invalidation
+	}
+	return defaultInvalidationChannel
+}
+
+// 在失效广播频道上发布缓存键，通知其它实例清理本地缓存
+func (rc *RedisCache) PublishInvalidation(ctx context.Context, key string) error {
+	return wrapRedisError(rc.rdb.Publish(ctx, rc.invalidationChannelName(), rc.buildKey(key)).Err())
+}
+
+// 通过一个 pipeline 为多个缓存键发布失效广播
+func (rc *RedisCache) publishInvalidations(ctx context.Context, cacheKeys []string) error {
+	channel := rc.invalidationChannelName()
+	pipe := rc.rdb.Pipeline()
+	for _, cacheKey := range cacheKeys {
+		pipe.Publish(ctx, channel, cacheKey)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// 订阅失效广播频道，收到 prefix 下的缓存键时以去掉 prefix 的键调用 fn。
+// 连接断开后自动重新订阅，断开期间发布的消息会丢失。方法阻塞直到 ctx 取消
+func (rc *RedisCache) OnInvalidation(ctx context.Context, fn func(key string)) error {
+	pubsub := rc.rdb.Subscribe(ctx, rc.invalidationChannelName())
+	defer pubsub.Close()
+
+	for {
+		// ReceiveMessage 遇到网络错误时会在下一次调用前重新建立连接并重新订阅
+		msg, err := pubsub.ReceiveMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(invalidationReconnectDelay):
+			}
+			continue
+		}
+
+		if strings.HasPrefix(msg.Payload, rc.prefix) {
+			fn(strings.TrimPrefix(msg.Payload, rc.prefix))
+		}
+	}
+}
+
+// 删除缓存键并在同一个 pipeline 中发布失效广播，channel 为空时使用 WithAutoInvalidation 的频道或默认频道。
+// 只返回删除的错误，发布失败不影响删除结果，没有订阅者时发布也不会失败
+func (rc *RedisCache) DeleteAndPublish(ctx context.Context, key string, channel string) error {
+	if len(channel) == 0 {
+		channel = rc.invalidationChannelName()
+	}
+
+	cacheKey := rc.buildKey(key)
+	pipe := rc.rdb.Pipeline()
+	delCmd := pipe.Del(ctx, cacheKey)
+	pipe.Publish(ctx, channel, cacheKey)
+	_, _ = pipe.Exec(ctx)
+	return wrapRedisError(delCmd.Err())
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 释放锁时锁已过期或已被其他持有者获取
+var ErrLockNotHeld = errors.New("cache: lock not held")
+
+// 获取锁失败时的重试间隔
+const lockRetryInterval = 50 * time.Millisecond
+
+// 只有持有者的 token 与锁中保存的一致时才删除，避免释放其他持有者在锁过期后重新获取的锁
+var unlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// 获取基于 SET NX PX 的分布式锁，ttl 后锁自动过期。
+// 锁被占用时每隔 lockRetryInterval 重试，直到获取成功或 ctx 结束；
+// 返回的 unlock 只会释放本次获取的锁，锁已过期时返回 ErrLockNotHeld
+func (rc *RedisCache) Lock(ctx context.Context, key string, ttl time.Duration) (unlock func() error, err error) {
+	token, err := newLockToken()
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := rc.buildKey(key)
+	for {
+		ok, err := rc.rdb.SetNX(ctx, cacheKey, token, ttl).Result()
+		if err != nil {
+			return nil, wrapRedisError(err)
+		}
+		if ok {
+			break
+		}
+
+		timer := time.NewTimer(lockRetryInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, wrapRedisError(ctx.Err())
+		case <-timer.C:
+		}
+	}
+
+	unlock = func() error {
+		// 释放锁不应因获取锁时的 ctx 已结束而失败
+		deleted, err := unlockScript.Run(context.Background(), rc.rdb, []string{cacheKey}, token).Int64()
+		if err != nil {
+			return wrapRedisError(err)
+		}
+		if deleted == 0 {
+			return ErrLockNotHeld
+		}
+		return nil
+	}
+	return unlock, nil
+}
+
+func newLockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"errors"
+	"time"
+
+	"github.com/duolacloud/crud-core/types"
+)
+
+// 缓存日志接口，通过 WithLogger 设置，keyvals 为交替出现的键值对
+type Logger interface {
+	Debug(msg string, keyvals ...any)
+	Error(msg string, keyvals ...any)
+}
+
+// 记录一次 Get / Set / Delete 操作，未命中按 debug 级别记录，其它错误按 error 级别记录
+func (rc *RedisCache) log(op string, key string, start time.Time, errp *error) {
+	d := time.Since(start)
+	err := *errp
+
+	if op == opGet && (err == nil || errors.Is(err, types.ErrNotFound)) {
+		rc.logger.Debug("cache "+op, "op", op, "key", key, "hit", err == nil, "duration", d)
+		return
+	}
+	if errors.Is(err, ErrConditionFailed) {
+		rc.logger.Debug("cache "+op+" skipped", "op", op, "key", key, "duration", d)
+		return
+	}
+	if err != nil {
+		rc.logger.Error("cache "+op+" failed", "op", op, "key", key, "duration", d, "error", err)
+		return
+	}
+	rc.logger.Debug("cache "+op, "op", op, "key", key, "duration", d)
+}
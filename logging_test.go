@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/duolacloud/crud-core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type testLogger struct {
+	debugs, errors []string
+}
+
+func (l *testLogger) Debug(msg string, keyvals ...any) { l.debugs = append(l.debugs, msg) }
+func (l *testLogger) Error(msg string, keyvals ...any) { l.errors = append(l.errors, msg) }
+
+func TestLoggerLog(t *testing.T) {
+	logger := &testLogger{}
+	rc := &RedisCache{logger: logger}
+
+	var err error
+	rc.log(opGet, "a", time.Now(), &err)
+	err = types.ErrNotFound
+	rc.log(opGet, "a", time.Now(), &err)
+	err = errors.New("connection refused")
+	rc.log(opSet, "a", time.Now(), &err)
+	err = nil
+	rc.log(opDelete, "a", time.Now(), &err)
+
+	assert.Equal(t, []string{"cache get", "cache get", "cache delete"}, logger.debugs)
+	assert.Equal(t, []string{"cache set failed"}, logger.errors)
+}
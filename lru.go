@@ -0,0 +1,158 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/duolacloud/crud-core/cache"
+	"github.com/duolacloud/crud-core/types"
+)
+
+// 基于内存的 LRU 缓存，容量满时淘汰最久未访问的键，适合作为 Tiered 的 L1，也可以通过 NewInMemory 在单元测试中代替 RedisCache。
+// 值以序列化后的字节数组保存，调用方修改取出的值不会影响缓存。
+// 序列化规则与 RedisCache 默认配置相同：[]byte 和 string 按原样保存，其它值使用 JSONCodec。
+// Set 支持 WithSetNX / WithSetXX，条件不满足时返回 ErrConditionFailed
+type LRUCache struct {
+	mu    sync.Mutex
+	size  int           // 最多保存的键数量，0 表示不限制
+	ttl   time.Duration // 默认过期时间，0 表示不过期
+	codec Codec
+	ll    *list.List
+	items map[string]*list.Element
+	now   func() time.Time
+}
+
+type lruEntry struct {
+	key      string
+	value    []byte
+	expireAt time.Time
+}
+
+// 创建最多保存 size 个键的 LRU 缓存，ttl 为默认过期时间，
+// Set 通过 cache.WithExpiration 或值实现的 Expirer 指定更短的过期时间时使用更短的过期时间
+func NewLRU(size int, ttl time.Duration) *LRUCache {
+	return &LRUCache{
+		size:  size,
+		ttl:   ttl,
+		codec: JSONCodec{},
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+		now:   time.Now,
+	}
+}
+
+func (c *LRUCache) Get(ctx context.Context, key string, value any, opts ...cache.GetOption) error {
+	if err := checkDestination(value); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	entry, ok := c.get(key)
+	c.mu.Unlock()
+	if !ok {
+		return types.ErrNotFound
+	}
+	return unmarshalValue(c.codec, entry.value, value)
+}
+
+func (c *LRUCache) Set(ctx context.Context, key string, value any, opts ...cache.SetOption) error {
+	options, condition := applySetOptions(opts)
+	bytes, err := marshalValue(c.codec, value)
+	if err != nil {
+		return err
+	}
+	bytes = append([]byte(nil), bytes...)
+
+	d := options.Exipration
+	if expirer, ok := value.(Expirer); ok && d == 0 {
+		d = expirer.CacheTTL()
+	}
+	ttl := c.ttl
+	if d > 0 && (ttl <= 0 || d < ttl) {
+		ttl = d
+	}
+	var expireAt time.Time
+	if ttl > 0 {
+		expireAt = c.now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// 与 redis 的 SET NX / XX 相同，已过期的键视为不存在
+	if condition != setAlways {
+		_, exists := c.peek(key)
+		if (condition == setIfAbsent) == exists {
+			return ErrConditionFailed
+		}
+	}
+
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		entry.value = bytes
+		entry.expireAt = expireAt
+		c.ll.MoveToFront(elem)
+		return nil
+	}
+
+	c.items[key] = c.ll.PushFront(&lruEntry{key: key, value: bytes, expireAt: expireAt})
+	for c.size > 0 && c.ll.Len() > c.size {
+		c.removeElement(c.ll.Back())
+	}
+	return nil
+}
+
+func (c *LRUCache) Delete(ctx context.Context, key string, opts ...cache.DeleteOption) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.removeElement(elem)
+	}
+	return nil
+}
+
+func (c *LRUCache) Exists(ctx context.Context, key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.get(key)
+	return ok, nil
+}
+
+// 当前保存的键数量，包括已过期但尚未被访问清理的键
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// 调用方需持有锁，过期的键在访问时清理
+func (c *LRUCache) get(key string) (*lruEntry, bool) {
+	entry, ok := c.peek(key)
+	if ok {
+		c.ll.MoveToFront(c.items[key])
+	}
+	return entry, ok
+}
+
+// 与 get 相同但不更新访问顺序
+func (c *LRUCache) peek(key string) (*lruEntry, bool) {
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*lruEntry)
+	if !entry.expireAt.IsZero() && !c.now().Before(entry.expireAt) {
+		c.removeElement(elem)
+		return nil, false
+	}
+	return entry, true
+}
+
+func (c *LRUCache) removeElement(elem *list.Element) {
+	c.ll.Remove(elem)
+	delete(c.items, elem.Value.(*lruEntry).key)
+}
@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/duolacloud/crud-core/cache"
+	"github.com/duolacloud/crud-core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCache(t *testing.T) {
+	c := NewLRU(2, time.Minute)
+
+	err := c.Set(context.TODO(), "key1", &User{Name: "jack"})
+	assert.Nil(t, err)
+	err = c.Set(context.TODO(), "key2", &User{Name: "rose"})
+	assert.Nil(t, err)
+
+	// 访问 key1 后 key2 成为最久未访问的键
+	found := new(User)
+	err = c.Get(context.TODO(), "key1", found)
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", found.Name)
+
+	err = c.Set(context.TODO(), "key3", &User{Name: "tom"})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, c.Len())
+
+	err = c.Get(context.TODO(), "key2", found)
+	assert.Same(t, err, types.ErrNotFound)
+
+	err = c.Set(context.TODO(), "key4", &User{Name: "lucy"}, cache.WithExpiration(50*time.Millisecond))
+	assert.Nil(t, err)
+	time.Sleep(100 * time.Millisecond)
+
+	exists, err := c.Exists(context.TODO(), "key4")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	err = c.Delete(context.TODO(), "key1")
+	assert.Nil(t, err)
+	err = c.Get(context.TODO(), "key1", found)
+	assert.Same(t, err, types.ErrNotFound)
+}
+
+func TestTiered(t *testing.T) {
+	l1 := NewLRU(10, time.Minute)
+	l2 := NewLRU(10, time.Minute)
+	c := NewTiered(l1, l2, WithL1TTL(time.Second))
+
+	// 只存在于 L2 的值读取后回填 L1
+	err := l2.Set(context.TODO(), "key1", &User{Name: "jack"})
+	assert.Nil(t, err)
+
+	found := new(User)
+	err = c.Get(context.TODO(), "key1", found)
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", found.Name)
+
+	exists, err := l1.Exists(context.TODO(), "key1")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	err = c.Set(context.TODO(), "key2", &User{Name: "rose"})
+	assert.Nil(t, err)
+	exists, err = l2.Exists(context.TODO(), "key2")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	err = c.Delete(context.TODO(), "key1")
+	assert.Nil(t, err)
+	exists, err = c.Exists(context.TODO(), "key1")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
+
+func TestTieredString(t *testing.T) {
+	l1 := NewLRU(10, time.Minute)
+	l2 := NewLRU(10, time.Minute)
+	c := NewTiered(l1, l2)
+
+	err := l2.Set(context.TODO(), "html", `<b>"bold"</b>`)
+	assert.Nil(t, err)
+
+	// 第一次从 L2 读取并用 *string 回填 L1，第二次命中 L1
+	for i := 0; i < 2; i++ {
+		var html string
+		err = c.Get(context.TODO(), "html", &html)
+		assert.Nil(t, err)
+		assert.Equal(t, `<b>"bold"</b>`, html)
+	}
+
+	raw := []byte{0x01, 0x02}
+	err = c.Set(context.TODO(), "raw", &raw)
+	assert.Nil(t, err)
+	var found []byte
+	err = l1.Get(context.TODO(), "raw", &found)
+	assert.Nil(t, err)
+	assert.Equal(t, raw, found)
+}
+
+func TestLRUCacheConditionalSet(t *testing.T) {
+	c := NewInMemory()
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	err := c.Set(context.TODO(), "key", &User{Name: "jack"}, WithSetXX())
+	assert.ErrorIs(t, err, ErrConditionFailed)
+	err = c.Set(context.TODO(), "key", &User{Name: "jack"}, WithSetNX(), cache.WithExpiration(time.Second))
+	assert.Nil(t, err)
+	err = c.Set(context.TODO(), "key", &User{Name: "rose"}, WithSetNX())
+	assert.ErrorIs(t, err, ErrConditionFailed)
+	err = c.Set(context.TODO(), "key", &User{Name: "lucy"}, WithSetXX(), cache.WithExpiration(time.Second))
+	assert.Nil(t, err)
+
+	found := new(User)
+	err = c.Get(context.TODO(), "key", found)
+	assert.Nil(t, err)
+	assert.Equal(t, "lucy", found.Name)
+
+	// 过期的键视为不存在
+	now = now.Add(2 * time.Second)
+	err = c.Set(context.TODO(), "key", &User{Name: "lily"}, WithSetXX())
+	assert.ErrorIs(t, err, ErrConditionFailed)
+	err = c.Set(context.TODO(), "key", &User{Name: "lily"}, WithSetNX())
+	assert.Nil(t, err)
+}
@@ -0,0 +1,8 @@
+package cache
+
+// 创建不限容量、没有默认过期时间的内存缓存，用于在单元测试中代替 RedisCache。
+// 过期时间的规则与 RedisCache 相同：cache.WithExpiration 优先，其次是值实现的 Expirer，0 或 NoExpiration 表示不过期。
+// 过期的键在访问时清理，未命中返回 types.ErrNotFound
+func NewInMemory() *LRUCache {
+	return NewLRU(0, 0)
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/duolacloud/crud-core/cache"
+	"github.com/duolacloud/crud-core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryCache(t *testing.T) {
+	var c cache.Cache = NewInMemory()
+
+	err := c.Set(context.TODO(), "key1", &User{Name: "jack", Age: 18})
+	assert.Nil(t, err)
+
+	found := new(User)
+	err = c.Get(context.TODO(), "key1", found)
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", found.Name)
+
+	err = c.Get(context.TODO(), "missing", found)
+	assert.Same(t, types.ErrNotFound, err)
+
+	// 与 RedisCache 相同，字符串按原样保存
+	err = c.Set(context.TODO(), "html", `<b>"bold"</b>`)
+	assert.Nil(t, err)
+	var html string
+	err = c.Get(context.TODO(), "html", &html)
+	assert.Nil(t, err)
+	assert.Equal(t, `<b>"bold"</b>`, html)
+
+	err = c.Get(context.TODO(), "html", found)
+	assert.ErrorIs(t, err, ErrSerialization)
+
+	err = c.Delete(context.TODO(), "key1")
+	assert.Nil(t, err)
+	exists, err := c.Exists(context.TODO(), "key1")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
+
+func TestInMemoryCacheExpiration(t *testing.T) {
+	c := NewInMemory()
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	err := c.Set(context.TODO(), "short", &User{Name: "jack"}, cache.WithExpiration(time.Second))
+	assert.Nil(t, err)
+	err = c.Set(context.TODO(), "forever", &User{Name: "rose"}, cache.WithExpiration(NoExpiration))
+	assert.Nil(t, err)
+	err = c.Set(context.TODO(), "expirer", &token{expiresAt: time.Now().Add(time.Second)})
+	assert.Nil(t, err)
+
+	now = now.Add(2 * time.Second)
+	for key, want := range map[string]bool{"short": false, "forever": true, "expirer": false} {
+		exists, err := c.Exists(context.TODO(), key)
+		assert.Nil(t, err)
+		assert.Equal(t, want, exists, key)
+	}
+	assert.Equal(t, 1, c.Len())
+}
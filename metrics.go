@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"errors"
+	"time"
+
+	"github.com/duolacloud/crud-core/types"
+)
+
+const (
+	opGet    = "get"
+	opSet    = "set"
+	opDelete = "delete"
+
+	// ObserveValueSize 的 op：压缩、加密之后实际写入 redis 的大小
+	opSetStored = "set_stored"
+	// ObserveError 的 op：Get 读取到的值无法还原
+	opDecode = "decode"
+)
+
+// 缓存指标采集器，通过 WithMetrics 设置
+type Collector interface {
+	// Get 命中或未命中（types.ErrNotFound）时调用
+	ObserveGet(hit bool, d time.Duration)
+	// Set 成功时调用
+	ObserveSet(d time.Duration)
+	// Delete 成功时调用
+	ObserveDelete(d time.Duration)
+	// 操作返回除未命中以外的错误时调用，op 为 get / set / delete；
+	// Get 读取到的值无法还原时还会以 decode 调用一次，即使 WithOnDecodeError 的回调将其当作未命中
+	ObserveError(op string)
+	// 记录缓存值的大小，op 为 set 时是 marshal 之后的大小，为 set_stored 时是压缩、加密之后写入 redis 的大小，
+	// 为 get 时是读取后解密、解压得到的大小
+	ObserveValueSize(op string, bytes int)
+}
+
+func (rc *RedisCache) observe(op string, start time.Time, errp *error) {
+	d := time.Since(start)
+	err := *errp
+
+	if op == opGet && (err == nil || errors.Is(err, types.ErrNotFound)) {
+		rc.metrics.ObserveGet(err == nil, d)
+		return
+	}
+	// 写入条件不满足是预期的结果，既不是写入也不是错误
+	if errors.Is(err, ErrConditionFailed) {
+		return
+	}
+	if err != nil {
+		rc.metrics.ObserveError(op)
+		return
+	}
+
+	switch op {
+	case opSet:
+		rc.metrics.ObserveSet(d)
+	case opDelete:
+		rc.metrics.ObserveDelete(d)
+	}
+}
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/duolacloud/crud-core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCollector struct {
+	hits, misses, sets, deletes int
+	errors                      []string
+	sizes                       map[string]int
+}
+
+func (c *testCollector) ObserveGet(hit bool, d time.Duration) {
+	if hit {
+		c.hits++
+	} else {
+		c.misses++
+	}
+}
+func (c *testCollector) ObserveSet(d time.Duration)    { c.sets++ }
+func (c *testCollector) ObserveDelete(d time.Duration) { c.deletes++ }
+func (c *testCollector) ObserveError(op string)        { c.errors = append(c.errors, op) }
+func (c *testCollector) ObserveValueSize(op string, bytes int) {
+	if c.sizes == nil {
+		c.sizes = make(map[string]int)
+	}
+	c.sizes[op] = bytes
+}
+
+func TestMetricsObserve(t *testing.T) {
+	collector := &testCollector{}
+	rc := &RedisCache{metrics: collector}
+
+	var err error
+	rc.observe(opGet, time.Now(), &err)
+	err = types.ErrNotFound
+	rc.observe(opGet, time.Now(), &err)
+	err = errors.New("connection refused")
+	rc.observe(opGet, time.Now(), &err)
+	rc.observe(opSet, time.Now(), &err)
+	err = ErrConditionFailed
+	rc.observe(opSet, time.Now(), &err)
+	err = nil
+	rc.observe(opSet, time.Now(), &err)
+	rc.observe(opDelete, time.Now(), &err)
+
+	assert.Equal(t, 1, collector.hits)
+	assert.Equal(t, 1, collector.misses)
+	assert.Equal(t, 1, collector.sets)
+	assert.Equal(t, 1, collector.deletes)
+	assert.Equal(t, []string{opGet, opSet}, collector.errors)
+}
+
+func TestStats(t *testing.T) {
+	rc := &RedisCache{stats: &statsCounters{}}
+
+	var err error
+	rc.count(opGet, &err)
+	err = types.ErrNotFound
+	rc.count(opGet, &err)
+	err = errors.New("connection refused")
+	rc.count(opSet, &err)
+	err = ErrConditionFailed
+	rc.count(opSet, &err)
+	err = nil
+	rc.count(opDelete, &err)
+
+	assert.Equal(t, Stats{Gets: 2, Hits: 1, Misses: 1, Deletes: 1, Skipped: 1, Errors: 1}, rc.Stats())
+
+	rc.ResetStats()
+	assert.Equal(t, Stats{}, rc.Stats())
+}
+
+func TestMetricsValueSize(t *testing.T) {
+	collector := &testCollector{}
+	rc := &RedisCache{codec: JSONCodec{}, metrics: collector, compression: true}
+
+	article := newArticle()
+	data, err := rc.encode(article)
+	assert.Nil(t, err)
+	err = rc.decode(data, new(Article))
+	assert.Nil(t, err)
+
+	assert.Equal(t, len(data), collector.sizes[opSetStored])
+	assert.True(t, collector.sizes[opSet] > collector.sizes[opSetStored])
+	assert.Equal(t, collector.sizes[opSet], collector.sizes[opGet])
+}
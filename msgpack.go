@@ -0,0 +1,25 @@
+package cache
+
+import "github.com/vmihailenco/msgpack/v5"
+
+// 基于 msgpack 的序列化函数，可以直接传给 WithMarshal，
+// 原生支持 time.Time，嵌入的 struct 字段会被展开
+func MsgpackMarshal(v any) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+// 基于 msgpack 的反序列化函数，可以直接传给 WithUnmarshal
+func MsgpackUnmarshal(data []byte, v any) error {
+	return msgpack.Unmarshal(data, v)
+}
+
+// 基于 msgpack 的 Codec，可以直接传给 WithCodec
+type MsgpackCodec struct{}
+
+func (MsgpackCodec) Marshal(v any) ([]byte, error) {
+	return MsgpackMarshal(v)
+}
+
+func (MsgpackCodec) Unmarshal(data []byte, v any) error {
+	return MsgpackUnmarshal(data, v)
+}
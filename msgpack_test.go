@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Audit struct {
+	CreatedAt time.Time `msgpack:"created_at" json:"created_at"`
+	UpdatedAt time.Time `msgpack:"updated_at" json:"updated_at"`
+}
+
+type Profile struct {
+	Audit
+	Name   string            `msgpack:"name" json:"name"`
+	Age    int               `msgpack:"age" json:"age"`
+	Emails []string          `msgpack:"emails" json:"emails"`
+	Extra  map[string]string `msgpack:"extra" json:"extra"`
+}
+
+func newProfile() *Profile {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	return &Profile{
+		Audit:  Audit{CreatedAt: now.Add(-time.Hour), UpdatedAt: now},
+		Name:   "jack",
+		Age:    18,
+		Emails: []string{"jack@example.com", "jack@example.org"},
+		Extra:  map[string]string{"city": "shanghai"},
+	}
+}
+
+func TestMsgpack(t *testing.T) {
+	profile := newProfile()
+
+	data, err := MsgpackMarshal(profile)
+	assert.Nil(t, err)
+
+	found := new(Profile)
+	err = MsgpackUnmarshal(data, found)
+	assert.Nil(t, err)
+	assert.True(t, profile.CreatedAt.Equal(found.CreatedAt))
+	assert.True(t, profile.UpdatedAt.Equal(found.UpdatedAt))
+	assert.Equal(t, profile.Name, found.Name)
+	assert.Equal(t, profile.Emails, found.Emails)
+	assert.Equal(t, profile.Extra, found.Extra)
+
+	jsonData, err := json.Marshal(profile)
+	assert.Nil(t, err)
+	t.Logf("json: %d bytes, msgpack: %d bytes", len(jsonData), len(data))
+}
+
+// go test -bench Codec -benchmem
+func BenchmarkCodecJSON(b *testing.B) {
+	profile := newProfile()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data, _ := json.Marshal(profile)
+		_ = json.Unmarshal(data, new(Profile))
+	}
+}
+
+func BenchmarkCodecMsgpack(b *testing.B) {
+	profile := newProfile()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data, _ := MsgpackMarshal(profile)
+		_ = MsgpackUnmarshal(data, new(Profile))
+	}
+}
@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"github.com/duolacloud/crud-core/cache"
+)
+
+// 创建共享同一个 redis 连接的子缓存，子缓存的前缀为 prefix+sub，其它配置与当前缓存相同。
+// 子缓存的 Close 不会关闭共享的连接，连接仍由当前缓存负责关闭
+func (rc *RedisCache) Namespace(sub string) cache.Cache {
+	child := &RedisCache{
+		prefix:          rc.prefix + sub,
+		hashKeysOver:    rc.hashKeysOver,
+		codec:           rc.codec,
+		addr:            rc.addr,
+		url:             rc.url,
+		network:         rc.network,
+		username:        rc.username,
+		password:        rc.password,
+		db:              rc.db,
+		client:          rc.client,
+		clientOptions:   rc.clientOptions,
+		clusterClient:   rc.clusterClient,
+		clusterOptions:  rc.clusterOptions,
+		failoverOptions: rc.failoverOptions,
+		universal:       rc.universal,
+		rdb:             rc.rdb,
+		reader:          rc.reader,
+		ownsClient:      false,
+		tls:             rc.tls,
+		tlsConfig:       rc.tlsConfig,
+		dialTimeout:     rc.dialTimeout,
+		readTimeout:     rc.readTimeout,
+		writeTimeout:    rc.writeTimeout,
+		poolSize:        rc.poolSize,
+		minIdleConns:    rc.minIdleConns,
+		poolTimeout:     rc.poolTimeout,
+		connMaxLifetime: rc.connMaxLifetime,
+		compression:     rc.compression,
+		compressor:      rc.compressor,
+		compressionMin:  rc.compressionMin,
+		encryptionKeys:  rc.encryptionKeys,
+		aeads:           rc.aeads,
+		deleteBatchSize: rc.deleteBatchSize,
+		scanCount:       rc.scanCount,
+		negativeTTL:     rc.negativeTTL,
+		jitterFraction:  rc.jitterFraction,
+		staleWindow:     rc.staleWindow,
+		refreshLoader:   rc.refreshLoader,
+		refreshBelow:    rc.refreshBelow,
+		casRetries:      rc.casRetries,
+		maxValueSize:    rc.maxValueSize,
+		ttlFunc:         rc.ttlFunc,
+		defaultTTL:      rc.defaultTTL,
+		opTimeout:       rc.opTimeout,
+		stats:           &statsCounters{},
+		fallback:        rc.fallback,
+		fallbackWrites:  rc.fallbackWrites,
+		hooks:           rc.hooks,
+		onDecodeError:   rc.onDecodeError,
+		autoInvalidate:  rc.autoInvalidate,
+		invalidation:    rc.invalidation,
+		logger:          rc.logger,
+		readReplicas:    rc.readReplicas,
+		breaker:         rc.breaker,
+		retryAttempts:   rc.retryAttempts,
+		retryBaseDelay:  rc.retryBaseDelay,
+		redisJSON:       rc.redisJSON,
+		schemaVersion:   rc.schemaVersion,
+		emptyAsMiss:     rc.emptyAsMiss,
+		emptyPredicate:  rc.emptyPredicate,
+		metrics:         rc.metrics,
+	}
+
+	if rc.keyBuilder != nil {
+		parent := rc.keyBuilder
+		child.keyBuilder = func(key string) string {
+			return parent(sub + key)
+		}
+	}
+	return child
+}
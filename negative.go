@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/duolacloud/crud-core/types"
+)
+
+// 命中通过 SetMiss 记录的未命中时，Get 返回的错误同时满足
+// errors.Is(err, types.ErrNotFound) 和 errors.Is(err, ErrCachedMiss)
+var ErrCachedMiss = errors.New("cache: cached miss")
+
+// 未命中记录在 redis 中保存的值
+var negativeValue = []byte{envelopeMagic, negativeEnvelope}
+
+func isNegative(data []byte) bool {
+	return bytes.Equal(data, negativeValue)
+}
+
+func cachedMissError() error {
+	return &classifiedError{kind: ErrCachedMiss, err: types.ErrNotFound}
+}
+
+// 记录缓存键在后端存储中不存在，在 WithNegativeCaching 设置的时间内 Get 直接返回 ErrCachedMiss。
+// 未开启 WithNegativeCaching 时不做任何操作
+func (rc *RedisCache) SetMiss(ctx context.Context, key string) error {
+	if rc.negativeTTL <= 0 {
+		return nil
+	}
+
+	cacheKey := rc.buildKey(key)
+	err := rc.rdb.Set(ctx, cacheKey, negativeValue, rc.negativeTTL).Err()
+	return wrapRedisError(err)
+}
+
+// GetOrSet / GetOrLoad 是否需要调用 loader：真正的未命中才需要，记录过的未命中不需要
+func needLoad(err error) bool {
+	return errors.Is(err, types.ErrNotFound) && !errors.Is(err, ErrCachedMiss)
+}
@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 订阅 redis 的键事件通知，prefix 下的键被修改、删除或过期时调用 onEvent，
+// key 为去掉 prefix 的键，event 为事件名，例如 set、del、expired。
+// 需要在 redis 上开启 notify-keyspace-events，例如 CONFIG SET notify-keyspace-events Egx$。
+// 方法阻塞直到 ctx 取消，集群模式下同时订阅所有 master 节点
+func (rc *RedisCache) Subscribe(ctx context.Context, onEvent func(key, event string)) error {
+	if rc.clusterClient != nil {
+		return rc.clusterClient.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			return rc.receiveEvents(ctx, client.PSubscribe(ctx, "__keyevent@0__:*"), onEvent)
+		})
+	}
+
+	db := 0
+	if rc.client != nil {
+		db = rc.client.Options().DB
+	}
+	return rc.receiveEvents(ctx, rc.rdb.PSubscribe(ctx, fmt.Sprintf("__keyevent@%d__:*", db)), onEvent)
+}
+
+func (rc *RedisCache) receiveEvents(ctx context.Context, pubsub *redis.PubSub, onEvent func(key, event string)) error {
+	defer pubsub.Close()
+
+	// 等待订阅确认，连接失败时立即返回错误
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return wrapRedisError(err)
+	}
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if !strings.HasPrefix(msg.Payload, rc.prefix) {
+				continue
+			}
+			event := msg.Channel[strings.LastIndex(msg.Channel, ":")+1:]
+			onEvent(strings.TrimPrefix(msg.Payload, rc.prefix), event)
+		}
+	}
+}
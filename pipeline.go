@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/duolacloud/crud-core/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// Pipeline 中可用的操作，键会自动加上前缀，值会自动序列化。
+// 操作只是排队，在 fn 返回后一次发送，返回的 PipelineResult 在 Pipeline 返回后才可读取
+type Pipeliner interface {
+	// 排队读取缓存，执行后反序列化到 value 中
+	Get(key string, value any) *PipelineResult
+	// 排队写入缓存
+	Set(key string, value any, opts ...cache.SetOption) *PipelineResult
+	// 排队删除缓存
+	Delete(key string) *PipelineResult
+}
+
+// Pipeline 中单个操作的结果
+type PipelineResult struct {
+	err error
+}
+
+// 操作的错误，读取未命中时为 types.ErrNotFound
+func (r *PipelineResult) Err() error {
+	return r.err
+}
+
+type pipeliner struct {
+	rc   *RedisCache
+	ctx  context.Context
+	pipe redis.Pipeliner
+	gets []pipelineGet
+	cmds []pipelineCmd
+}
+
+type pipelineGet struct {
+	cmd    *redis.StringCmd
+	value  any
+	result *PipelineResult
+}
+
+type pipelineCmd struct {
+	cmd    redis.Cmder
+	result *PipelineResult
+}
+
+func (p *pipeliner) Get(key string, value any) *PipelineResult {
+	result := &PipelineResult{}
+	cmd := p.pipe.Get(p.ctx, p.rc.buildKey(key))
+	p.gets = append(p.gets, pipelineGet{cmd: cmd, value: value, result: result})
+	return result
+}
+
+func (p *pipeliner) Set(key string, value any, opts ...cache.SetOption) *PipelineResult {
+	result := &PipelineResult{}
+	options, err := setOptions(opts)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	bytes, err := p.rc.encode(value)
+	if err != nil {
+		result.err = err
+		return result
+	}
+
+	cmd := p.pipe.Set(p.ctx, p.rc.buildKey(key), bytes, p.rc.expiration(options, value))
+	p.cmds = append(p.cmds, pipelineCmd{cmd: cmd, result: result})
+	return result
+}
+
+func (p *pipeliner) Delete(key string) *PipelineResult {
+	result := &PipelineResult{}
+	cmd := p.pipe.Del(p.ctx, p.rc.buildKey(key))
+	p.cmds = append(p.cmds, pipelineCmd{cmd: cmd, result: result})
+	return result
+}
+
+// 在一次往返中执行 fn 中排队的所有操作，fn 返回错误时放弃所有操作。
+// 各个操作的结果通过其 PipelineResult 读取；有操作失败时返回第一个失败的错误，读取未命中不算失败
+func (rc *RedisCache) Pipeline(ctx context.Context, fn func(p Pipeliner) error) error {
+	p := &pipeliner{rc: rc, ctx: ctx, pipe: rc.rdb.Pipeline()}
+	if err := fn(p); err != nil {
+		p.pipe.Discard()
+		return err
+	}
+
+	if p.pipe.Len() == 0 {
+		return nil
+	}
+
+	_, execErr := p.pipe.Exec(ctx)
+	if errors.Is(execErr, redis.Nil) {
+		execErr = nil
+	}
+
+	for _, get := range p.gets {
+		bytes, err := get.cmd.Bytes()
+		if err != nil {
+			get.result.err = wrapRedisError(err)
+			continue
+		}
+		if isNegative(bytes) {
+			get.result.err = cachedMissError()
+			continue
+		}
+		get.result.err = rc.decode(bytes, get.value)
+	}
+	for _, c := range p.cmds {
+		c.result.err = wrapRedisError(c.cmd.Err())
+	}
+	return wrapRedisError(execErr)
+}
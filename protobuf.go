@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"reflect"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var protoMessageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+// 值实现了 proto.Message 时使用 proto.Marshal / proto.Unmarshal，
+// 否则使用 Fallback，Fallback 为空时使用 JSONCodec
+type ProtoCodec struct {
+	Fallback Codec
+}
+
+func (c ProtoCodec) Marshal(v any) ([]byte, error) {
+	if m, ok := v.(proto.Message); ok {
+		return proto.Marshal(m)
+	}
+	return c.fallback().Marshal(v)
+}
+
+func (c ProtoCodec) Unmarshal(data []byte, v any) error {
+	if m, ok := asProtoMessage(v); ok {
+		return proto.Unmarshal(data, m)
+	}
+	return c.fallback().Unmarshal(data, v)
+}
+
+func (c ProtoCodec) fallback() Codec {
+	if c.Fallback == nil {
+		return JSONCodec{}
+	}
+	return c.Fallback
+}
+
+// v 是反序列化的目标，可能是 *pb.User 本身、指向 any 的指针（any 中保存着 *pb.User），
+// 也可能是 **pb.User（例如 Typed[*pb.User].Get），*pb.User 为 nil 时会先分配
+func asProtoMessage(v any) (proto.Message, bool) {
+	if m, ok := v.(proto.Message); ok {
+		return m, true
+	}
+	if p, ok := v.(*any); ok && p != nil {
+		m, ok := (*p).(proto.Message)
+		return m, ok
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return nil, false
+	}
+	elem := rv.Elem()
+	if elem.Kind() != reflect.Pointer || !elem.Type().Implements(protoMessageType) {
+		return nil, false
+	}
+	if elem.IsNil() {
+		elem.Set(reflect.New(elem.Type().Elem()))
+	}
+	return elem.Interface().(proto.Message), true
+}
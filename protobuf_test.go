@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestProtoCodec(t *testing.T) {
+	codec := ProtoCodec{}
+
+	data, err := codec.Marshal(wrapperspb.String("jack"))
+	assert.Nil(t, err)
+
+	found := new(wrapperspb.StringValue)
+	err = codec.Unmarshal(data, found)
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", found.GetValue())
+
+	// 目标是指向 any 的指针时，对 any 中保存的 proto.Message 反序列化
+	found = new(wrapperspb.StringValue)
+	var value any = found
+	err = codec.Unmarshal(data, &value)
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", found.GetValue())
+
+	// 目标是 **T 时分配 *T 后反序列化
+	var ptr *wrapperspb.StringValue
+	err = codec.Unmarshal(data, &ptr)
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", ptr.GetValue())
+
+	// 非 proto.Message 使用 JSON
+	data, err = codec.Marshal(&User{Name: "rose"})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"name":"rose","age":0}`, string(data))
+
+	foundUser := new(User)
+	err = codec.Unmarshal(data, foundUser)
+	assert.Nil(t, err)
+	assert.Equal(t, "rose", foundUser.Name)
+}
@@ -2,11 +2,16 @@ package cache
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/duolacloud/crud-core/cache"
 	"github.com/duolacloud/crud-core/types"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -63,3 +68,1109 @@ func TestRedisCache(t *testing.T) {
 	assert.False(t, exists)
 
 }
+
+func TestRedisCachePing(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	defer redisCache.(*RedisCache).Close()
+
+	err = redisCache.(*RedisCache).Ping(context.TODO())
+	assert.Nil(t, err)
+
+	// 已取消的 ctx 直接返回错误
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	err = redisCache.(*RedisCache).Ping(ctx)
+	assert.NotNil(t, err)
+}
+
+func TestRedisCacheClose(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+
+	err = redisCache.(*RedisCache).Close()
+	assert.Nil(t, err)
+
+	// 关闭后连接池不可再用
+	err = redisCache.Set(context.TODO(), "close", &User{Name: "close"})
+	assert.NotNil(t, err)
+
+	// 通过 WithClient 传入的连接不会被关闭
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer client.Close()
+	redisCache, err = New(WithClient(client))
+	assert.Nil(t, err)
+	err = redisCache.(*RedisCache).Close()
+	assert.Nil(t, err)
+	assert.Nil(t, client.Ping(context.TODO()).Err())
+}
+
+func TestRedisCacheCountExists(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Set(context.TODO(), "count_key1", &User{Name: "jack"}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+	err = rc.Set(context.TODO(), "count_key2", &User{Name: "rose"}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+
+	count, err := rc.CountExists(context.TODO(), "count_key1", "count_key2", "count_key3")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+
+	exists, err := rc.Exists(context.TODO(), "count_key3")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
+
+func TestRedisCacheTTL(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Set(context.TODO(), "ttl_key1", &User{Name: "jack"}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+
+	ttl, err := rc.TTL(context.TODO(), "ttl_key1")
+	assert.Nil(t, err)
+	assert.True(t, ttl > 0 && ttl <= 5*time.Second)
+
+	err = rc.Set(context.TODO(), "ttl_key2", &User{Name: "rose"})
+	assert.Nil(t, err)
+
+	ttl, err = rc.TTL(context.TODO(), "ttl_key2")
+	assert.Nil(t, err)
+	assert.Equal(t, NoExpiration, ttl)
+
+	err = rc.Expire(context.TODO(), "ttl_key2", 10*time.Second)
+	assert.Nil(t, err)
+
+	ttl, err = rc.TTL(context.TODO(), "ttl_key2")
+	assert.Nil(t, err)
+	assert.True(t, ttl > 5*time.Second && ttl <= 10*time.Second)
+
+	err = rc.Delete(context.TODO(), "ttl_key2")
+	assert.Nil(t, err)
+
+	_, err = rc.TTL(context.TODO(), "ttl_key2")
+	assert.Same(t, err, types.ErrNotFound)
+
+	err = rc.Expire(context.TODO(), "ttl_key2", 10*time.Second)
+	assert.Same(t, err, types.ErrNotFound)
+}
+
+func TestRedisCacheMGet(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.MSet(context.TODO(), map[string]any{
+		"mget_key1": &User{Name: "jack", Age: 18},
+		"mget_key3": &User{Name: "rose", Age: 20},
+	}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+
+	var users []*User
+	hits, err := rc.MGet(context.TODO(), []string{"mget_key1", "mget_key2", "mget_key3"}, &users)
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true, false, true}, hits)
+	assert.Len(t, users, 3)
+	assert.Equal(t, "jack", users[0].Name)
+	assert.Nil(t, users[1])
+	assert.Equal(t, "rose", users[2].Name)
+
+	var values []User
+	_, err = rc.MGet(context.TODO(), []string{"mget_key1"}, &values)
+	assert.Nil(t, err)
+	assert.Equal(t, 18, values[0].Age)
+
+	// 损坏的值不影响其它键
+	err = rc.Set(context.TODO(), "mget_corrupt", "not json", cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+	hits, err = rc.MGet(context.TODO(), []string{"mget_key1", "mget_corrupt", "mget_key3"}, &values)
+	var multiErr *MultiError
+	assert.ErrorAs(t, err, &multiErr)
+	assert.Len(t, multiErr.Errors, 1)
+	assert.ErrorIs(t, multiErr.Errors[1], ErrSerialization)
+	assert.Equal(t, []bool{true, false, true}, hits)
+	assert.Equal(t, "jack", values[0].Name)
+	assert.Equal(t, User{}, values[1])
+	assert.Equal(t, "rose", values[2].Name)
+}
+
+func TestRedisCacheDeleteMany(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"), WithDeleteBatchSize(2))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	keys := []string{"del_key1", "del_key2", "del_key3"}
+	for _, key := range keys {
+		err = rc.Set(context.TODO(), key, &User{Name: key}, cache.WithExpiration(5*time.Second))
+		assert.Nil(t, err)
+	}
+
+	deleted, err := rc.DeleteMany(context.TODO(), append(keys, "del_missing"))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), deleted)
+
+	count, err := rc.CountExists(context.TODO(), keys...)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestRedisCacheDeleteExisting(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Set(context.TODO(), "del_existing", &User{Name: "jack"}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+
+	existed, err := rc.DeleteExisting(context.TODO(), "del_existing")
+	assert.Nil(t, err)
+	assert.True(t, existed)
+
+	existed, err = rc.DeleteExisting(context.TODO(), "del_existing")
+	assert.Nil(t, err)
+	assert.False(t, existed)
+}
+
+func TestRedisCacheIncrement(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Delete(context.TODO(), "incr_key1")
+	assert.Nil(t, err)
+
+	value, err := rc.Increment(context.TODO(), "incr_key1", 3, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), value)
+
+	value, err = rc.Decrement(context.TODO(), "incr_key1", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), value)
+
+	ttl, err := rc.TTL(context.TODO(), "incr_key1")
+	assert.Nil(t, err)
+	assert.True(t, ttl > 0 && ttl <= 5*time.Second)
+}
+
+func TestRedisCacheSetNX(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Delete(context.TODO(), "setnx_key1")
+	assert.Nil(t, err)
+
+	ok, err := rc.SetNX(context.TODO(), "setnx_key1", &User{Name: "jack"}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = rc.SetNX(context.TODO(), "setnx_key1", &User{Name: "rose"}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	foundUser := new(User)
+	err = rc.Get(context.TODO(), "setnx_key1", foundUser)
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", foundUser.Name)
+}
+
+func TestRedisCacheGetOrSet(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Delete(context.TODO(), "getorset_key1")
+	assert.Nil(t, err)
+
+	calls := 0
+	loader := func(ctx context.Context) (any, error) {
+		calls++
+		return &User{Name: "jack", Age: 18}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		foundUser := new(User)
+		err = rc.GetOrSet(context.TODO(), "getorset_key1", foundUser, loader, cache.WithExpiration(5*time.Second))
+		assert.Nil(t, err)
+		assert.Equal(t, "jack", foundUser.Name)
+	}
+	assert.Equal(t, 1, calls)
+}
+
+func TestRedisCacheGetOrLoad(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Delete(context.TODO(), "getorload_key1")
+	assert.Nil(t, err)
+
+	var calls int32
+	loader := func(ctx context.Context) (any, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(100 * time.Millisecond)
+		return &User{Name: "jack", Age: 18}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			foundUser := new(User)
+			err := rc.GetOrLoad(context.TODO(), "getorload_key1", foundUser, loader, cache.WithExpiration(5*time.Second))
+			assert.Nil(t, err)
+			assert.Equal(t, "jack", foundUser.Name)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+// 记录 Unmarshal 收到的目标，用于验证 Get 原样传递调用方的指针
+type recordingCodec struct {
+	JSONCodec
+	dest any
+}
+
+func (c *recordingCodec) Unmarshal(data []byte, v any) error {
+	c.dest = v
+	return c.JSONCodec.Unmarshal(data, v)
+}
+
+func TestRedisCacheGetPassesPointer(t *testing.T) {
+	codec := &recordingCodec{}
+	redisCache, err := New(WithPrefix("curd-cache-redis:"), WithCodec(codec))
+	assert.Nil(t, err)
+
+	err = redisCache.Set(context.TODO(), "pointer_key1", &User{Name: "jack"}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+
+	foundUser := new(User)
+	err = redisCache.Get(context.TODO(), "pointer_key1", foundUser)
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", foundUser.Name)
+
+	dest, ok := codec.dest.(*User)
+	assert.True(t, ok)
+	assert.Same(t, foundUser, dest)
+}
+
+func TestRedisCacheURL(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"), WithURL("redis://localhost:6379/0"))
+	assert.Nil(t, err)
+
+	err = redisCache.(*RedisCache).Ping(context.TODO())
+	assert.Nil(t, err)
+
+	_, err = New(WithURL("http://localhost:6379"))
+	assert.NotNil(t, err)
+}
+
+func TestRedisCacheDeletePrefix(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	for i := 0; i < 10; i++ {
+		err = rc.Set(context.TODO(), fmt.Sprintf("tenant:123:key%d", i), &User{Name: "jack"}, cache.WithExpiration(5*time.Second))
+		assert.Nil(t, err)
+	}
+	err = rc.Set(context.TODO(), "tenant:456:key0", &User{Name: "rose"}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+
+	count, err := rc.CountPrefix(context.TODO(), "tenant:123:", WithCount(3))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10), count)
+
+	err = rc.DeletePrefix(context.TODO(), "tenant:123:", WithCount(3))
+	assert.Nil(t, err)
+
+	count, err = rc.CountPrefix(context.TODO(), "tenant:123:")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+
+	exists, err := rc.Exists(context.TODO(), "tenant:123:key0")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	exists, err = rc.Exists(context.TODO(), "tenant:456:key0")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+}
+
+func TestRedisCacheTags(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.SetWithTags(context.TODO(), "product:1:page", &User{Name: "page"}, []string{"product:1", "price"}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+	err = rc.SetWithTags(context.TODO(), "product:2:page", &User{Name: "page"}, []string{"product:2", "price"}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+
+	err = rc.InvalidateTag(context.TODO(), "product:1")
+	assert.Nil(t, err)
+
+	count, err := rc.CountExists(context.TODO(), "product:1:page", "product:2:page")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+
+	err = rc.InvalidateTag(context.TODO(), "price")
+	assert.Nil(t, err)
+
+	count, err = rc.CountExists(context.TODO(), "product:1:page", "product:2:page")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+// 让 MULTI 事务失败的 go-redis 钩子
+type failTxHook struct {
+	fail bool
+}
+
+func (h *failTxHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+func (h *failTxHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return next
+}
+func (h *failTxHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if h.fail && len(cmds) > 0 && cmds[0].Name() == "multi" {
+			return errors.New("ERR injected failure")
+		}
+		return next(ctx, cmds)
+	}
+}
+
+func TestRedisCacheInvalidateTagRetry(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+	hook := &failTxHook{fail: true}
+	rc.client.AddHook(hook)
+
+	err = rc.SetWithTags(context.TODO(), "retry:1", &User{Name: "page"}, []string{"retry"}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+
+	// 删除失败时标签集合保持不变，重试可以继续删除
+	err = rc.InvalidateTag(context.TODO(), "retry")
+	assert.NotNil(t, err)
+	members, err := rc.client.SCard(context.TODO(), rc.tagKey("retry")).Result()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), members)
+
+	hook.fail = false
+	err = rc.InvalidateTag(context.TODO(), "retry")
+	assert.Nil(t, err)
+	exists, err := rc.Exists(context.TODO(), "retry:1")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
+
+func TestRedisCacheNegativeCaching(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"), WithNegativeCaching(5*time.Second))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Delete(context.TODO(), "negative_key1")
+	assert.Nil(t, err)
+
+	calls := 0
+	loader := func(ctx context.Context) (any, error) {
+		calls++
+		return nil, types.ErrNotFound
+	}
+
+	for i := 0; i < 2; i++ {
+		err = rc.GetOrSet(context.TODO(), "negative_key1", new(User), loader)
+		assert.ErrorIs(t, err, types.ErrNotFound)
+	}
+	assert.Equal(t, 1, calls)
+
+	err = rc.Get(context.TODO(), "negative_key1", new(User))
+	assert.ErrorIs(t, err, types.ErrNotFound)
+	assert.ErrorIs(t, err, ErrCachedMiss)
+}
+
+func TestRedisCacheLock(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	unlock, err := rc.Lock(context.TODO(), "lock_key1", 5*time.Second)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 200*time.Millisecond)
+	defer cancel()
+	_, err = rc.Lock(ctx, "lock_key1", 5*time.Second)
+	assert.ErrorIs(t, err, ErrTimeout)
+
+	err = unlock()
+	assert.Nil(t, err)
+	err = unlock()
+	assert.ErrorIs(t, err, ErrLockNotHeld)
+
+	unlock, err = rc.Lock(context.TODO(), "lock_key1", 5*time.Second)
+	assert.Nil(t, err)
+	assert.Nil(t, unlock())
+}
+
+func TestRedisCachePipeline(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Set(context.TODO(), "pipeline_key1", &User{Name: "jack"}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+
+	foundUser1 := new(User)
+	foundUser3 := new(User)
+	var get1, get3, set2, del1 *PipelineResult
+	err = rc.Pipeline(context.TODO(), func(p Pipeliner) error {
+		get1 = p.Get("pipeline_key1", foundUser1)
+		set2 = p.Set("pipeline_key2", &User{Name: "rose"}, cache.WithExpiration(5*time.Second))
+		get3 = p.Get("pipeline_key3", foundUser3)
+		del1 = p.Delete("pipeline_key1")
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, get1.Err())
+	assert.Equal(t, "jack", foundUser1.Name)
+	assert.Nil(t, set2.Err())
+	assert.Same(t, get3.Err(), types.ErrNotFound)
+	assert.Nil(t, del1.Err())
+
+	exists, err := rc.Exists(context.TODO(), "pipeline_key1")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
+
+func TestRedisCacheTransaction(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Set(context.TODO(), "tx_key1", &User{Name: "jack"}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+
+	err = rc.Transaction(context.TODO(), func(tx Tx) error {
+		if err := tx.Set("tx_key2", &User{Name: "rose"}, cache.WithExpiration(5*time.Second)); err != nil {
+			return err
+		}
+		return tx.Delete("tx_key1")
+	})
+	assert.Nil(t, err)
+
+	user := new(User)
+	err = rc.Get(context.TODO(), "tx_key2", user)
+	assert.Nil(t, err)
+	assert.Equal(t, "rose", user.Name)
+	exists, err := rc.Exists(context.TODO(), "tx_key1")
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	// 任意一个操作失败时都不生效
+	err = rc.Transaction(context.TODO(), func(tx Tx) error {
+		_ = tx.Delete("tx_key2")
+		_ = tx.Set("tx_key3", make(chan int))
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrSerialization)
+	exists, err = rc.Exists(context.TODO(), "tx_key2")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+}
+
+func TestRedisCacheGetSet(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Delete(context.TODO(), "getset_key1")
+	assert.Nil(t, err)
+
+	old := new(User)
+	err = rc.GetSet(context.TODO(), "getset_key1", &User{Name: "jack"}, old)
+	assert.Same(t, err, types.ErrNotFound)
+
+	err = rc.GetSet(context.TODO(), "getset_key1", &User{Name: "rose"}, old)
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", old.Name)
+
+	ttl, err := rc.TTL(context.TODO(), "getset_key1")
+	assert.Nil(t, err)
+	assert.Equal(t, NoExpiration, ttl)
+
+	err = rc.GetSet(context.TODO(), "getset_key1", &User{Name: "lucy"}, old, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+	assert.Equal(t, "rose", old.Name)
+
+	// 没有设置过期时间时保留原有的过期时间
+	err = rc.GetSet(context.TODO(), "getset_key1", &User{Name: "lily"}, old)
+	assert.Nil(t, err)
+	assert.Equal(t, "lucy", old.Name)
+	ttl, err = rc.TTL(context.TODO(), "getset_key1")
+	assert.Nil(t, err)
+	assert.True(t, ttl > 0 && ttl <= 5*time.Second)
+
+	err = rc.Delete(context.TODO(), "getset_key1")
+	assert.Nil(t, err)
+}
+
+func TestRedisCacheCompareAndSwap(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Delete(context.TODO(), "cas_key1")
+	assert.Nil(t, err)
+
+	swapped, err := rc.CompareAndSwap(context.TODO(), "cas_key1", nil, &User{Name: "jack", Age: 1}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+	assert.True(t, swapped)
+
+	swapped, err = rc.CompareAndSwap(context.TODO(), "cas_key1", &User{Name: "jack", Age: 2}, &User{Name: "jack", Age: 3})
+	assert.Nil(t, err)
+	assert.False(t, swapped)
+
+	swapped, err = rc.CompareAndSwap(context.TODO(), "cas_key1", &User{Name: "jack", Age: 1}, &User{Name: "jack", Age: 2})
+	assert.Nil(t, err)
+	assert.True(t, swapped)
+
+	foundUser := new(User)
+	err = rc.Get(context.TODO(), "cas_key1", foundUser)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, foundUser.Age)
+}
+
+func TestRedisCacheKeyBuilder(t *testing.T) {
+	redisCache, err := New(WithKeyBuilder(func(key string) string {
+		return "curd-cache-redis:{tenant1}:v2:" + key
+	}))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Set(context.TODO(), "builder_key1", &User{Name: "jack"}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+
+	exists, err := rc.rdb.Exists(context.TODO(), "curd-cache-redis:{tenant1}:v2:builder_key1").Result()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), exists)
+}
+
+func TestRedisCacheHashFields(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Delete(context.TODO(), "session:1")
+	assert.Nil(t, err)
+
+	err = rc.SetFields(context.TODO(), "session:1", map[string]any{"visits": 1, "last_seen": 100}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+	err = rc.SetFields(context.TODO(), "session:1", map[string]any{"last_seen": 200})
+	assert.Nil(t, err)
+
+	var lastSeen int
+	err = rc.GetField(context.TODO(), "session:1", "last_seen", &lastSeen)
+	assert.Nil(t, err)
+	assert.Equal(t, 200, lastSeen)
+
+	err = rc.GetField(context.TODO(), "session:1", "missing", &lastSeen)
+	assert.ErrorIs(t, err, types.ErrNotFound)
+
+	var fields map[string]int
+	err = rc.GetFields(context.TODO(), "session:1", &fields)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{"visits": 1, "last_seen": 200}, fields)
+
+	ttl, err := rc.TTL(context.TODO(), "session:1")
+	assert.Nil(t, err)
+	assert.True(t, ttl > 0)
+}
+
+func TestRedisCacheGroup(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Delete(context.TODO(), "flags")
+	assert.Nil(t, err)
+
+	err = rc.GroupSet(context.TODO(), "flags", "user:1", true, 5*time.Second)
+	assert.Nil(t, err)
+	err = rc.GroupSet(context.TODO(), "flags", "user:2", false, 0)
+	assert.Nil(t, err)
+
+	var flag bool
+	err = rc.GroupGet(context.TODO(), "flags", "user:1", &flag)
+	assert.Nil(t, err)
+	assert.True(t, flag)
+
+	err = rc.GroupGet(context.TODO(), "flags", "user:3", &flag)
+	assert.ErrorIs(t, err, types.ErrNotFound)
+
+	// ttl 为 0 时保留分组的过期时间
+	ttl, err := rc.TTL(context.TODO(), "flags")
+	assert.Nil(t, err)
+	assert.True(t, ttl > 0)
+}
+
+func TestRedisCacheRedisJSON(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"), WithRedisJSON())
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Set(context.TODO(), "json:1", &User{Name: "jack"}, cache.WithExpiration(5*time.Second))
+	if errors.Is(err, ErrRedisJSONUnavailable) {
+		t.Skip("RedisJSON module is not loaded")
+	}
+	assert.Nil(t, err)
+
+	var user User
+	err = rc.Get(context.TODO(), "json:1", &user)
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", user.Name)
+
+	var name string
+	err = rc.GetPath(context.TODO(), "json:1", ".name", &name)
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", name)
+}
+
+func TestRedisCacheGetWithTTL(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Set(context.TODO(), "ttl_get", &User{Name: "jack"}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+
+	var user User
+	ttl, err := rc.GetWithTTL(context.TODO(), "ttl_get", &user)
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", user.Name)
+	assert.True(t, ttl > 0 && ttl <= 5*time.Second)
+
+	err = rc.Delete(context.TODO(), "ttl_get")
+	assert.Nil(t, err)
+	_, err = rc.GetWithTTL(context.TODO(), "ttl_get", &user)
+	assert.ErrorIs(t, err, types.ErrNotFound)
+}
+
+func TestRedisCacheWarm(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	_, err = rc.DeleteMany(context.TODO(), []string{"warm:1", "warm:2"})
+	assert.Nil(t, err)
+
+	err = rc.Warm(context.TODO(), map[string]any{"warm:1": &User{Name: "jack"}}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+
+	written, err := rc.WarmMissing(context.TODO(), map[string]any{
+		"warm:1": &User{Name: "rose"},
+		"warm:2": &User{Name: "rose"},
+	}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, written)
+
+	var user User
+	err = rc.Get(context.TODO(), "warm:1", &user)
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", user.Name)
+}
+
+func TestRedisCacheWarmConcurrent(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	items := make(map[string]any, 1200)
+	for i := 0; i < 1200; i++ {
+		items[fmt.Sprintf("warm_concurrent:%d", i)] = &User{Name: "jack", Age: i}
+	}
+	err = rc.WarmConcurrent(context.TODO(), items, 2, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+
+	count, err := rc.CountExists(context.TODO(), "warm_concurrent:0", "warm_concurrent:1199")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestRedisCacheScan(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	for i := 0; i < 5; i++ {
+		err = rc.Set(context.TODO(), fmt.Sprintf("scan:key%d", i), &User{Name: "jack"}, cache.WithExpiration(5*time.Second))
+		assert.Nil(t, err)
+	}
+
+	var keys []string
+	err = rc.Scan(context.TODO(), "scan:*", func(key string) error {
+		keys = append(keys, key)
+		return nil
+	}, WithCount(2))
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(keys))
+	assert.Contains(t, keys, "scan:key0")
+
+	stop := errors.New("stop")
+	err = rc.Scan(context.TODO(), "scan:*", func(key string) error {
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+}
+
+func TestRedisCacheSubscribe(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.rdb.ConfigSet(context.TODO(), "notify-keyspace-events", "Egx$").Err()
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	events := make(chan string, 10)
+	go rc.Subscribe(ctx, func(key, event string) {
+		events <- key + ":" + event
+	})
+	time.Sleep(100 * time.Millisecond)
+
+	err = rc.Set(context.TODO(), "notify", &User{Name: "jack"})
+	assert.Nil(t, err)
+	err = rc.Delete(context.TODO(), "notify")
+	assert.Nil(t, err)
+
+	assert.Equal(t, "notify:set", <-events)
+	assert.Equal(t, "notify:del", <-events)
+}
+
+func TestRedisCacheInvalidation(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"), WithAutoInvalidation("curd-cache-redis:invalidate"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	keys := make(chan string, 10)
+	go rc.OnInvalidation(ctx, func(key string) {
+		keys <- key
+	})
+	time.Sleep(100 * time.Millisecond)
+
+	err = rc.Delete(context.TODO(), "invalidate:1")
+	assert.Nil(t, err)
+	_, err = rc.DeleteMany(context.TODO(), []string{"invalidate:2"})
+	assert.Nil(t, err)
+	err = rc.PublishInvalidation(context.TODO(), "invalidate:3")
+	assert.Nil(t, err)
+	err = rc.DeleteAndPublish(context.TODO(), "invalidate:4", "")
+	assert.Nil(t, err)
+
+	assert.Equal(t, "invalidate:1", <-keys)
+	assert.Equal(t, "invalidate:2", <-keys)
+	assert.Equal(t, "invalidate:3", <-keys)
+	assert.Equal(t, "invalidate:4", <-keys)
+}
+
+func TestRedisCacheSetIfNewer(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Delete(context.TODO(), "versioned")
+	assert.Nil(t, err)
+
+	ok, err := rc.SetIfNewer(context.TODO(), "versioned", &User{Name: "v2"}, 2, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = rc.SetIfNewer(context.TODO(), "versioned", &User{Name: "v1"}, 1, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	ok, err = rc.SetIfNewer(context.TODO(), "versioned", &User{Name: "v10"}, 10, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	var user User
+	err = rc.Get(context.TODO(), "versioned", &user)
+	assert.Nil(t, err)
+	assert.Equal(t, "v10", user.Name)
+}
+
+func TestRedisCacheNamespace(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	users := rc.Namespace("users:").(*RedisCache)
+	err = users.Set(context.TODO(), "1", &User{Name: "jack"}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+
+	var user User
+	err = rc.Get(context.TODO(), "users:1", &user)
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", user.Name)
+
+	// 子缓存不会关闭共享的连接
+	err = users.Close()
+	assert.Nil(t, err)
+	err = rc.Ping(context.TODO())
+	assert.Nil(t, err)
+}
+
+func TestRedisCacheSetEntries(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.SetEntries(context.TODO(), []Entry{
+		{Key: "entry:short", Value: &User{Name: "jack"}, TTL: 5 * time.Second},
+		{Key: "entry:long", Value: &User{Name: "rose"}, TTL: time.Minute},
+	})
+	assert.Nil(t, err)
+
+	ttl, err := rc.TTL(context.TODO(), "entry:short")
+	assert.Nil(t, err)
+	assert.True(t, ttl <= 5*time.Second)
+
+	ttl, err = rc.TTL(context.TODO(), "entry:long")
+	assert.Nil(t, err)
+	assert.True(t, ttl > 5*time.Second)
+
+	// 编码失败时不写入任何键
+	err = rc.SetEntries(context.TODO(), []Entry{
+		{Key: "entry:short", Value: &User{Name: "lucy"}},
+		{Key: "entry:bad", Value: make(chan int)},
+	})
+	assert.NotNil(t, err)
+
+	var user User
+	err = rc.Get(context.TODO(), "entry:short", &user)
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", user.Name)
+}
+
+func TestRedisCacheIncrementEx(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Delete(context.TODO(), "rate:1")
+	assert.Nil(t, err)
+
+	value, err := rc.IncrementEx(context.TODO(), "rate:1", 1, 5*time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), value)
+
+	time.Sleep(100 * time.Millisecond)
+	ttl, err := rc.TTL(context.TODO(), "rate:1")
+	assert.Nil(t, err)
+
+	// 后续的增加不刷新过期时间
+	value, err = rc.IncrementEx(context.TODO(), "rate:1", 1, 5*time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), value)
+
+	newTTL, err := rc.TTL(context.TODO(), "rate:1")
+	assert.Nil(t, err)
+	assert.True(t, newTTL <= ttl)
+}
+
+func TestRedisCacheClear(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis-clear:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	for i := 0; i < 3; i++ {
+		err = rc.Set(context.TODO(), fmt.Sprintf("key%d", i), &User{Name: "jack"}, cache.WithExpiration(5*time.Second))
+		assert.Nil(t, err)
+	}
+
+	err = rc.Clear(context.TODO())
+	assert.Nil(t, err)
+
+	count, err := rc.CountPrefix(context.TODO(), "")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestRedisCacheReaderClient(t *testing.T) {
+	reader := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer reader.Close()
+
+	redisCache, err := New(WithPrefix("curd-cache-redis:"), WithReaderClient(reader))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Set(context.TODO(), "reader", &User{Name: "jack"}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+
+	var user User
+	err = rc.Get(context.TODO(), "reader", &user)
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", user.Name)
+
+	exists, err := rc.Exists(context.TODO(), "reader")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+}
+
+func TestRedisCacheRefreshAhead(t *testing.T) {
+	var loads int32
+	redisCache, err := New(
+		WithPrefix("curd-cache-redis:"),
+		WithDefaultExpiration(10*time.Second),
+		WithRefreshAhead(5*time.Second, func(ctx context.Context, key string) (any, error) {
+			atomic.AddInt32(&loads, 1)
+			return &User{Name: "refreshed"}, nil
+		}),
+	)
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Set(context.TODO(), "refresh", &User{Name: "jack"}, cache.WithExpiration(time.Second))
+	assert.Nil(t, err)
+
+	var user User
+	err = rc.Get(context.TODO(), "refresh", &user)
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", user.Name)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&loads))
+
+	err = rc.Get(context.TODO(), "refresh", &user)
+	assert.Nil(t, err)
+	assert.Equal(t, "refreshed", user.Name)
+
+	ttl, err := rc.TTL(context.TODO(), "refresh")
+	assert.Nil(t, err)
+	assert.True(t, ttl > 5*time.Second)
+}
+
+func TestRedisCacheRefreshAheadTTL(t *testing.T) {
+	loader := func(ctx context.Context, key string) (any, error) {
+		return &User{Name: "refreshed"}, nil
+	}
+	// 没有过期策略时刷新无法延长过期时间
+	_, err := New(WithRefreshAhead(5*time.Second, loader))
+	assert.NotNil(t, err)
+
+	redisCache, err := New(
+		WithPrefix("curd-cache-redis:"),
+		WithTTLFunc(func(value any) time.Duration { return 30 * time.Second }),
+		WithRefreshAhead(5*time.Second, loader),
+	)
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Set(context.TODO(), "refresh_ttl", &User{Name: "jack"}, cache.WithExpiration(2*time.Second))
+	assert.Nil(t, err)
+	before, err := rc.TTL(context.TODO(), "refresh_ttl")
+	assert.Nil(t, err)
+
+	var user User
+	err = rc.Get(context.TODO(), "refresh_ttl", &user)
+	assert.Nil(t, err)
+	time.Sleep(100 * time.Millisecond)
+
+	// 刷新后的过期时间由 WithTTLFunc 决定，不再低于阈值
+	after, err := rc.TTL(context.TODO(), "refresh_ttl")
+	assert.Nil(t, err)
+	assert.True(t, before <= 2*time.Second)
+	assert.True(t, after > 5*time.Second)
+}
+
+func TestRedisCacheExistsMany(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Set(context.TODO(), "exists:1", &User{Name: "jack"}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+	err = rc.Delete(context.TODO(), "exists:2")
+	assert.Nil(t, err)
+
+	exists, err := rc.ExistsMany(context.TODO(), []string{"exists:2", "exists:1", "exists:2", "exists:1"})
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{false, true, false, true}, exists)
+}
+
+func TestRedisCacheConditionalSet(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Delete(context.TODO(), "conditional")
+	assert.Nil(t, err)
+
+	err = rc.Set(context.TODO(), "conditional", &User{Name: "jack"}, WithSetXX())
+	assert.ErrorIs(t, err, ErrConditionFailed)
+
+	err = rc.Set(context.TODO(), "conditional", &User{Name: "jack"}, WithSetNX(), cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+
+	err = rc.Set(context.TODO(), "conditional", &User{Name: "rose"}, WithSetNX())
+	assert.ErrorIs(t, err, ErrConditionFailed)
+
+	err = rc.Set(context.TODO(), "conditional", &User{Name: "lucy"}, WithSetXX(), cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+
+	user := &User{}
+	err = rc.Get(context.TODO(), "conditional", user)
+	assert.Nil(t, err)
+	assert.Equal(t, "lucy", user.Name)
+
+	// 加载期间其它写入者先写入时，GetOrSet / GetOrLoad 返回已写入的值
+	for i, getOrSet := range []func(context.Context, string, any, func(context.Context) (any, error), ...cache.SetOption) error{rc.GetOrSet, rc.GetOrLoad} {
+		key := fmt.Sprintf("conditional_race%d", i)
+		err = rc.Delete(context.TODO(), key)
+		assert.Nil(t, err)
+
+		found := &User{}
+		err = getOrSet(context.TODO(), key, found, func(ctx context.Context) (any, error) {
+			if err := rc.Set(ctx, key, &User{Name: "winner"}, cache.WithExpiration(5*time.Second)); err != nil {
+				return nil, err
+			}
+			return &User{Name: "loser"}, nil
+		}, WithSetNX(), cache.WithExpiration(5*time.Second))
+		assert.Nil(t, err)
+		assert.Equal(t, "winner", found.Name)
+
+		// 键不存在时 XX 不写入，返回加载的值
+		err = rc.Delete(context.TODO(), key)
+		assert.Nil(t, err)
+		found = &User{}
+		err = getOrSet(context.TODO(), key, found, func(ctx context.Context) (any, error) {
+			return &User{Name: "loaded"}, nil
+		}, WithSetXX())
+		assert.Nil(t, err)
+		assert.Equal(t, "loaded", found.Name)
+	}
+
+	// 批量写入不支持写入条件，返回错误而不是忽略条件照常写入
+	err = rc.MSet(context.TODO(), map[string]any{"conditional": &User{Name: "rose"}}, WithSetNX())
+	assert.ErrorIs(t, err, ErrConditionUnsupported)
+	err = rc.Warm(context.TODO(), map[string]any{"conditional": &User{Name: "rose"}}, WithSetNX())
+	assert.ErrorIs(t, err, ErrConditionUnsupported)
+	err = rc.SetEntries(context.TODO(), []Entry{{Key: "conditional", Value: &User{Name: "rose"}}}, WithSetXX())
+	assert.ErrorIs(t, err, ErrConditionUnsupported)
+
+	err = rc.Get(context.TODO(), "conditional", user)
+	assert.Nil(t, err)
+	assert.Equal(t, "lucy", user.Name)
+}
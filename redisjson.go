@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 未加载 RedisJSON 模块时 redis 返回的错误前缀
+const unknownCommandPrefix = "ERR unknown command"
+
+// 将 RedisJSON 命令的错误适配为本包导出的错误
+func wrapJSONError(err error) error {
+	if err != nil && strings.HasPrefix(err.Error(), unknownCommandPrefix) {
+		return &classifiedError{kind: ErrRedisJSONUnavailable, err: err}
+	}
+	return wrapRedisError(err)
+}
+
+// 使用 JSON.SET 写入整个文档，值始终以 JSON 序列化，不经过 codec、压缩和加密。
+// JSON.SET 不支持过期时间，因此在同一事务中设置或清除过期时间，与 SET 的语义保持一致
+func (rc *RedisCache) setJSON(ctx context.Context, cacheKey string, value any, expiration time.Duration) error {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return &classifiedError{kind: ErrSerialization, err: err}
+	}
+
+	_, err = rc.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.JSONSet(ctx, cacheKey, "$", bytes)
+		if expiration > 0 {
+			pipe.PExpire(ctx, cacheKey, expiration)
+		} else {
+			pipe.Persist(ctx, cacheKey)
+		}
+		return nil
+	})
+	return wrapJSONError(err)
+}
+
+func (rc *RedisCache) getJSON(ctx context.Context, cacheKey string, path string, value any) error {
+	var paths []string
+	if len(path) > 0 {
+		paths = append(paths, path)
+	}
+
+	data, err := rc.rdb.JSONGet(ctx, cacheKey, paths...).Result()
+	if err != nil {
+		return wrapJSONError(err)
+	}
+	if len(data) == 0 {
+		return wrapRedisError(redis.Nil)
+	}
+	if err := json.Unmarshal([]byte(data), value); err != nil {
+		return &classifiedError{kind: ErrSerialization, err: err}
+	}
+	return nil
+}
+
+// 使用 JSON.GET 读取文档中 path 指向的部分，需要启用 WithRedisJSON。
+// 旧式路径（如 ".profile.name"）返回单个值，JSONPath（如 "$.profile.name"）返回匹配结果的数组，
+// 键不存在时返回 types.ErrNotFound
+func (rc *RedisCache) GetPath(ctx context.Context, key string, path string, dest any) error {
+	return rc.getJSON(ctx, rc.buildKey(key), path, dest)
+}
@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/duolacloud/crud-core/cache"
+)
+
+// 刷新缓存的加载函数，key 为调用方传入的键
+type RefreshFunc func(ctx context.Context, key string) (any, error)
+
+// 在一次往返中读取缓存值和剩余过期时间，没有过期时间时返回 0
+func (rc *RedisCache) getWithPTTL(ctx context.Context, cacheKey string) ([]byte, time.Duration, error) {
+	pipe := rc.readClient().Pipeline()
+	getCmd := pipe.Get(ctx, cacheKey)
+	ttlCmd := pipe.PTTL(ctx, cacheKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	bytes, err := getCmd.Bytes()
+	if err != nil {
+		return nil, 0, err
+	}
+	ttl := ttlCmd.Val()
+	if ttl < 0 {
+		ttl = 0
+	}
+	return bytes, ttl, nil
+}
+
+// 剩余过期时间低于阈值时在后台重新加载，同一个键同时只有一个刷新任务。
+// 刷新写入的过期时间由值实现的 Expirer、WithTTLFunc 和 WithDefaultExpiration 决定，
+// New 要求后两者至少设置一个，否则刷新后的键无法得到新的过期时间
+func (rc *RedisCache) refreshAhead(key string, cacheKey string, ttl time.Duration) {
+	if ttl <= 0 || ttl >= rc.refreshBelow {
+		return
+	}
+	if _, loaded := rc.revalidating.LoadOrStore(cacheKey, struct{}{}); loaded {
+		return
+	}
+
+	go func() {
+		defer rc.revalidating.Delete(cacheKey)
+
+		ctx := context.Background()
+		value, err := rc.refreshLoader(ctx, key)
+		if err != nil {
+			return
+		}
+		bytes, err := rc.encode(value)
+		if err != nil {
+			return
+		}
+		_ = rc.rdb.Set(ctx, cacheKey, bytes, rc.expiration(&cache.SetOptions{}, value)).Err()
+	}()
+}
@@ -0,0 +1,29 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadFromReplicas(t *testing.T) {
+	c, err := New(
+		WithClusterOptions(&redis.ClusterOptions{Addrs: []string{"localhost:7000"}}),
+		WithReadFromReplicas(true),
+	)
+	assert.Nil(t, err)
+	defer c.(*RedisCache).Close()
+	options := c.(*RedisCache).clusterClient.Options()
+	assert.True(t, options.ReadOnly)
+	assert.True(t, options.RouteRandomly)
+
+	c, err = New(
+		WithFailover(&redis.FailoverOptions{MasterName: "mymaster", SentinelAddrs: []string{"localhost:26379"}}),
+		WithReadFromReplicas(true),
+	)
+	assert.Nil(t, err)
+	defer c.(*RedisCache).Close()
+	assert.NotNil(t, c.(*RedisCache).clusterClient)
+	assert.Nil(t, c.(*RedisCache).client)
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"time"
+)
+
+// 判断错误是否为可以重试的网络错误，未命中、ctx 超时和取消等不重试
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// 第 attempt 次重试前的等待时间，按 baseDelay 指数增长，在 [d/2, d] 之间随机抖动
+func (rc *RedisCache) retryDelay(attempt int) time.Duration {
+	d := rc.retryBaseDelay << (attempt - 1)
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+// 执行一次 redis 调用，按熔断器和重试设置包装，返回适配后的错误。
+// 熔断器只统计重试结束后的最终结果
+func (rc *RedisCache) call(ctx context.Context, fn func() error) error {
+	if err := rc.breakerAllow(); err != nil {
+		return err
+	}
+
+	err := fn()
+	for attempt := 1; attempt < rc.retryAttempts && err != nil && isRetryable(err); attempt++ {
+		delay := rc.retryDelay(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			break
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			err = ctx.Err()
+		case <-timer.C:
+			err = fn()
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	err = wrapRedisError(err)
+	rc.breakerRecord(err)
+	return err
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+	rc := &RedisCache{retryAttempts: 3, retryBaseDelay: time.Millisecond}
+
+	calls := 0
+	err := rc.call(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return io.EOF
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, calls)
+
+	// 逻辑错误不重试
+	calls = 0
+	err = rc.call(context.Background(), func() error {
+		calls++
+		return redis.Nil
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, calls)
+
+	// ctx 取消后不再重试
+	ctx, cancel := context.WithCancel(context.Background())
+	calls = 0
+	err = rc.call(ctx, func() error {
+		calls++
+		cancel()
+		return io.EOF
+	})
+	assert.ErrorIs(t, err, ErrCanceled)
+	assert.Equal(t, 1, calls)
+
+	assert.False(t, isRetryable(errors.New("WRONGTYPE")))
+}
+
+func TestOperationContext(t *testing.T) {
+	rc := &RedisCache{opTimeout: time.Second}
+
+	ctx, cancel := rc.operationContext(context.Background())
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.True(t, time.Until(deadline) <= time.Second)
+
+	// 已有的截止时间保持不变
+	parent, parentCancel := context.WithTimeout(context.Background(), time.Hour)
+	defer parentCancel()
+	ctx, cancel = rc.operationContext(parent)
+	defer cancel()
+	deadline, _ = ctx.Deadline()
+	assert.True(t, time.Until(deadline) > time.Minute)
+}
@@ -0,0 +1,158 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SCAN 未通过 WithScanCount 或 WithCount 指定 COUNT 时使用的批量大小
+const defaultScanCount = 100
+
+type ScanOptions struct {
+	Count            int64 // SCAN 的 COUNT 提示，每次迭代大约返回的键数量
+	AllowEmptyPrefix bool  // 允许 Clear 在没有前缀时清空整个 db
+}
+
+type ScanOption func(*ScanOptions)
+
+// 设置本次调用 SCAN 的 COUNT 提示，优先于 WithScanCount
+func WithCount(count int64) ScanOption {
+	return func(o *ScanOptions) {
+		o.Count = count
+	}
+}
+
+// 允许 Clear 在缓存没有前缀时删除当前 db 的所有键
+func AllowEmptyPrefix() ScanOption {
+	return func(o *ScanOptions) {
+		o.AllowEmptyPrefix = true
+	}
+}
+
+func (rc *RedisCache) scanOptions(opts []ScanOption) *ScanOptions {
+	options := &ScanOptions{Count: defaultScanCount}
+	if rc.scanCount > 0 {
+		options.Count = int64(rc.scanCount)
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// 转义 SCAN MATCH 中的通配符，使前缀按字面匹配
+func escapeMatch(s string) string {
+	return matchEscaper.Replace(s)
+}
+
+var matchEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+// 使用 SCAN 遍历匹配 match 的缓存键，每批调用一次 fn，集群模式下遍历所有 master 节点。
+// 每次迭代前检查 ctx，取消后立即停止
+func (rc *RedisCache) scan(ctx context.Context, match string, count int64, fn func(cacheKeys []string) error) error {
+	scanNode := func(ctx context.Context, client redis.Cmdable) error {
+		var cursor uint64
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			cacheKeys, next, err := client.Scan(ctx, cursor, match, count).Result()
+			if err != nil {
+				return err
+			}
+
+			if len(cacheKeys) > 0 {
+				if err := fn(cacheKeys); err != nil {
+					return err
+				}
+			}
+
+			cursor = next
+			if cursor == 0 {
+				return nil
+			}
+		}
+	}
+
+	if rc.clusterClient != nil && rc.reader == nil {
+		return rc.clusterClient.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			return scanNode(ctx, client)
+		})
+	}
+	return scanNode(ctx, rc.readClient())
+}
+
+// 删除 prefix+subPrefix 开头的所有缓存键，使用 SCAN 分批遍历并删除，不会使用阻塞 redis 的 KEYS
+func (rc *RedisCache) DeletePrefix(ctx context.Context, subPrefix string, opts ...ScanOption) error {
+	options := rc.scanOptions(opts)
+
+	err := rc.scan(ctx, escapeMatch(rc.prefix+subPrefix)+"*", options.Count, func(cacheKeys []string) error {
+		_, err := rc.del(ctx, cacheKeys...)
+		return err
+	})
+	return wrapRedisError(err)
+}
+
+// 统计 prefix+subPrefix 开头的缓存键数量，使用 SCAN 分批遍历，不会使用阻塞 redis 的 KEYS。
+// 遍历期间键的增删可能使结果略有偏差
+func (rc *RedisCache) CountPrefix(ctx context.Context, subPrefix string, opts ...ScanOption) (int64, error) {
+	options := rc.scanOptions(opts)
+
+	// 集群模式下各节点并发遍历，需要原子累加
+	var count int64
+	err := rc.scan(ctx, escapeMatch(rc.prefix+subPrefix)+"*", options.Count, func(cacheKeys []string) error {
+		atomic.AddInt64(&count, int64(len(cacheKeys)))
+		return nil
+	})
+	if err != nil {
+		return 0, wrapRedisError(err)
+	}
+	return count, nil
+}
+
+// 使用 SCAN 遍历 prefix 下匹配 match 的缓存键，match 支持 SCAN 的通配符，空字符串匹配所有键。
+// 每个键去掉 prefix 后传给 fn，fn 返回错误时停止遍历并返回该错误。
+// 集群模式下各节点并发遍历，但 fn 不会被并发调用
+func (rc *RedisCache) Scan(ctx context.Context, match string, fn func(key string) error, opts ...ScanOption) error {
+	options := rc.scanOptions(opts)
+	if len(match) == 0 {
+		match = "*"
+	}
+
+	var mu sync.Mutex
+	var stopErr error
+	err := rc.scan(ctx, escapeMatch(rc.prefix)+match, options.Count, func(cacheKeys []string) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if stopErr != nil {
+			return stopErr
+		}
+		for _, cacheKey := range cacheKeys {
+			if err := fn(strings.TrimPrefix(cacheKey, rc.prefix)); err != nil {
+				stopErr = err
+				return err
+			}
+		}
+		return nil
+	})
+	if stopErr != nil {
+		return stopErr
+	}
+	return wrapRedisError(err)
+}
+
+// 删除 prefix 开头的所有缓存键，使用 SCAN 分批遍历并删除。
+// prefix 为空时会删除整个 db，因此默认返回 ErrEmptyPrefix，需要显式传入 AllowEmptyPrefix()
+func (rc *RedisCache) Clear(ctx context.Context, opts ...ScanOption) error {
+	options := rc.scanOptions(opts)
+	if len(rc.prefix) == 0 && !options.AllowEmptyPrefix {
+		return ErrEmptyPrefix
+	}
+	return rc.DeletePrefix(ctx, "", opts...)
+}
@@ -0,0 +1,27 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeMatch(t *testing.T) {
+	assert.Equal(t, "app:", escapeMatch("app:"))
+	assert.Equal(t, `app:\*:\?:\[1\]:\\`, escapeMatch(`app:*:?:[1]:\`))
+}
+
+func TestClearEmptyPrefix(t *testing.T) {
+	rc := &RedisCache{}
+	assert.ErrorIs(t, rc.Clear(context.Background()), ErrEmptyPrefix)
+}
+
+func TestScanCount(t *testing.T) {
+	rc := &RedisCache{}
+	assert.Equal(t, int64(defaultScanCount), rc.scanOptions(nil).Count)
+
+	rc = &RedisCache{scanCount: 1000}
+	assert.Equal(t, int64(1000), rc.scanOptions(nil).Count)
+	assert.Equal(t, int64(10), rc.scanOptions([]ScanOption{WithCount(10)}).Count)
+}
@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"errors"
+
+	"github.com/duolacloud/crud-core/types"
+)
+
+// 开启 WithSchemaVersion 后，marshal 得到的字节数组在压缩和加密前加上 envelopeMagic、
+// schemaEnvelope 和一个字节的结构版本号
+const schemaHeaderBytes = 3
+
+// 缓存值的结构版本号与 WithSchemaVersion 设置的不同时，Get 返回的错误同时满足
+// errors.Is(err, types.ErrNotFound) 和 errors.Is(err, ErrSchemaMismatch)，GetOrSet / GetOrLoad 会重新加载
+var ErrSchemaMismatch = errors.New("cache: schema version mismatch")
+
+// 在 marshal 后的字节数组前加上结构版本号头
+func (rc *RedisCache) addSchemaVersion(data []byte) []byte {
+	if rc.schemaVersion <= 0 {
+		return data
+	}
+	out := make([]byte, 0, schemaHeaderBytes+len(data))
+	out = append(out, envelopeMagic, schemaEnvelope, byte(rc.schemaVersion))
+	return append(out, data...)
+}
+
+// 检查并去掉结构版本号头；开启 WithSchemaVersion 时，版本号不同或开启前写入的没有版本号的值都视为未命中
+func (rc *RedisCache) checkSchemaVersion(data []byte) ([]byte, error) {
+	hasHeader := hasEnvelope(data, schemaEnvelope, schemaHeaderBytes)
+	if rc.schemaVersion <= 0 {
+		if hasHeader {
+			return data[schemaHeaderBytes:], nil
+		}
+		return data, nil
+	}
+	if !hasHeader || int(data[2]) != rc.schemaVersion {
+		return nil, &classifiedError{kind: ErrSchemaMismatch, err: types.ErrNotFound}
+	}
+	return data[schemaHeaderBytes:], nil
+}
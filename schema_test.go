@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/duolacloud/crud-core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	v1 := &RedisCache{codec: JSONCodec{}, schemaVersion: 1}
+	v2 := &RedisCache{codec: JSONCodec{}, schemaVersion: 2, compression: true}
+	unversioned := &RedisCache{codec: JSONCodec{}}
+
+	data, err := v1.encode(&User{Name: "jack"})
+	assert.Nil(t, err)
+
+	var user User
+	err = v1.decode(data, &user)
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", user.Name)
+
+	// 版本号不同视为未命中
+	err = v2.decode(data, &user)
+	assert.ErrorIs(t, err, ErrSchemaMismatch)
+	assert.ErrorIs(t, err, types.ErrNotFound)
+	assert.True(t, needLoad(err))
+
+	// 开启前写入的值没有版本号
+	plain, err := unversioned.encode(&User{Name: "rose"})
+	assert.Nil(t, err)
+	err = v1.decode(plain, &user)
+	assert.ErrorIs(t, err, ErrSchemaMismatch)
+
+	// 关闭后仍然可以读取带版本号的值
+	err = unversioned.decode(data, &user)
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", user.Name)
+
+	data, err = v2.encode(&User{Name: "lucy"})
+	assert.Nil(t, err)
+	err = v2.decode(data, &user)
+	assert.Nil(t, err)
+	assert.Equal(t, "lucy", user.Name)
+
+	_, err = New(WithSchemaVersion(256))
+	assert.NotNil(t, err)
+}
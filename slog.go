@@ -0,0 +1,28 @@
+//go:build go1.21
+
+package cache
+
+import (
+	"context"
+	"log/slog"
+)
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+// 将 *slog.Logger 适配为 Logger，l 为 nil 时使用 slog.Default()
+func NewSlogLogger(l *slog.Logger) Logger {
+	if l == nil {
+		l = slog.Default()
+	}
+	return slogLogger{l: l}
+}
+
+func (s slogLogger) Debug(msg string, keyvals ...any) {
+	s.l.Log(context.Background(), slog.LevelDebug, msg, keyvals...)
+}
+
+func (s slogLogger) Error(msg string, keyvals ...any) {
+	s.l.Log(context.Background(), slog.LevelError, msg, keyvals...)
+}
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+
+	"github.com/duolacloud/crud-core/cache"
+)
+
+// GetStale 写入的值以 envelopeMagic、staleEnvelope 和 8 字节的软过期时间（unix 毫秒）开头
+const staleHeaderBytes = 10
+
+// 读取缓存，超过软过期时间但未超过硬过期时间时直接返回旧值，并在后台调用 revalidate 刷新，
+// 同一个缓存键同时只有一个后台刷新。
+// opts 中的过期时间为软过期时间，硬过期时间在此基础上延长 WithStaleWindow 设置的窗口；
+// 缓存未命中或结构版本号不同时同步调用 revalidate，命中 SetMiss 记录的未命中时与 Get 相同返回 ErrCachedMiss。
+// 不是由 GetStale 写入的值视为永不陈旧
+func (rc *RedisCache) GetStale(ctx context.Context, key string, value any, revalidate func() (any, error), opts ...cache.SetOption) error {
+	options, err := setOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	cacheKey := rc.buildKey(key)
+	data, err := rc.rdb.Get(ctx, cacheKey).Bytes()
+	var softExpireAt time.Time
+	switch {
+	case err != nil:
+		err = wrapRedisError(err)
+	case isNegative(data):
+		return cachedMissError()
+	default:
+		// 结构版本号不同时与 Get 相同视为未命中，同步重新加载
+		softExpireAt, data, err = rc.openEnvelope(data)
+	}
+	if err != nil {
+		if !needLoad(err) {
+			return err
+		}
+
+		loaded, err := revalidate()
+		if err != nil {
+			return err
+		}
+		if err := rc.setStale(ctx, cacheKey, loaded, options.Exipration); err != nil {
+			return err
+		}
+
+		bytes, err := rc.marshal(loaded)
+		if err != nil {
+			return err
+		}
+		return rc.unmarshal(bytes, value)
+	}
+
+	if err := rc.unmarshal(data, value); err != nil {
+		return err
+	}
+
+	if !softExpireAt.IsZero() && time.Now().After(softExpireAt) {
+		rc.revalidateInBackground(cacheKey, revalidate, options.Exipration)
+	}
+	return nil
+}
+
+func (rc *RedisCache) revalidateInBackground(cacheKey string, revalidate func() (any, error), soft time.Duration) {
+	if _, loaded := rc.revalidating.LoadOrStore(cacheKey, struct{}{}); loaded {
+		return
+	}
+
+	go func() {
+		defer rc.revalidating.Delete(cacheKey)
+
+		loaded, err := revalidate()
+		if err != nil {
+			return
+		}
+		_ = rc.setStale(context.Background(), cacheKey, loaded, soft)
+	}()
+}
+
+func (rc *RedisCache) setStale(ctx context.Context, cacheKey string, value any, soft time.Duration) error {
+	bytes, err := rc.encode(value)
+	if err != nil {
+		return err
+	}
+
+	var softExpireAt int64
+	hard := time.Duration(0)
+	if soft > 0 {
+		window := rc.staleWindow
+		if window <= 0 {
+			window = soft
+		}
+		softExpireAt = time.Now().Add(soft).UnixMilli()
+		hard = soft + window
+	}
+
+	data := make([]byte, staleHeaderBytes, staleHeaderBytes+len(bytes))
+	data[0], data[1] = envelopeMagic, staleEnvelope
+	binary.BigEndian.PutUint64(data[2:], uint64(softExpireAt))
+	data = append(data, bytes...)
+
+	err = rc.rdb.Set(ctx, cacheKey, data, hard).Err()
+	return wrapRedisError(err)
+}
+
+// 解析软过期时间，返回零值表示没有软过期时间
+func parseStale(data []byte) (time.Time, []byte) {
+	if !hasEnvelope(data, staleEnvelope, staleHeaderBytes) {
+		return time.Time{}, data
+	}
+
+	softExpireAt := int64(binary.BigEndian.Uint64(data[2:staleHeaderBytes]))
+	if softExpireAt == 0 {
+		return time.Time{}, data[staleHeaderBytes:]
+	}
+	return time.UnixMilli(softExpireAt), data[staleHeaderBytes:]
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/duolacloud/crud-core/cache"
+	"github.com/duolacloud/crud-core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStale(t *testing.T) {
+	softExpireAt, data := parseStale([]byte(`{"name":"jack"}`))
+	assert.True(t, softExpireAt.IsZero())
+	assert.Equal(t, `{"name":"jack"}`, string(data))
+}
+
+func TestRedisCacheGetStale(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"), WithStaleWindow(5*time.Second))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	err = rc.Delete(context.TODO(), "stale_key1")
+	assert.Nil(t, err)
+
+	version := 0
+	revalidate := func() (any, error) {
+		version++
+		return &User{Name: "jack", Age: version}, nil
+	}
+
+	found := new(User)
+	err = rc.GetStale(context.TODO(), "stale_key1", found, revalidate, cache.WithExpiration(time.Second))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, found.Age)
+
+	// 超过软过期时间后返回旧值，并在后台刷新
+	time.Sleep(1500 * time.Millisecond)
+	found = new(User)
+	err = rc.GetStale(context.TODO(), "stale_key1", found, revalidate, cache.WithExpiration(time.Second))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, found.Age)
+
+	time.Sleep(200 * time.Millisecond)
+	found = new(User)
+	err = rc.Get(context.TODO(), "stale_key1", found)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, found.Age)
+}
+
+func TestRedisCacheGetStaleMiss(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"), WithNegativeCaching(5*time.Second))
+	assert.Nil(t, err)
+	rc := redisCache.(*RedisCache)
+
+	loads := 0
+	revalidate := func() (any, error) {
+		loads++
+		return &User{Name: "jack"}, nil
+	}
+
+	// 记录过的未命中与 Get 相同直接返回，不解码到 value 中
+	err = rc.SetMiss(context.TODO(), "stale_miss")
+	assert.Nil(t, err)
+	found := new(User)
+	err = rc.GetStale(context.TODO(), "stale_miss", found, revalidate, cache.WithExpiration(time.Second))
+	assert.ErrorIs(t, err, types.ErrNotFound)
+	assert.ErrorIs(t, err, ErrCachedMiss)
+	assert.Equal(t, 0, loads)
+
+	// 结构版本号不同的值视为未命中，同步重新加载
+	v1, err := New(WithPrefix("curd-cache-redis:"), WithSchemaVersion(1))
+	assert.Nil(t, err)
+	v2, err := New(WithPrefix("curd-cache-redis:"), WithSchemaVersion(2))
+	assert.Nil(t, err)
+	err = v1.Set(context.TODO(), "stale_schema", &User{Name: "old"}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+	err = v2.(*RedisCache).GetStale(context.TODO(), "stale_schema", found, revalidate, cache.WithExpiration(time.Second))
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", found.Name)
+	assert.Equal(t, 1, loads)
+}
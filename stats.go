@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"github.com/duolacloud/crud-core/types"
+)
+
+// 缓存实例的操作计数，由 Stats 返回
+type Stats struct {
+	Gets    int64 // Get 调用次数
+	Hits    int64 // Get 命中次数
+	Misses  int64 // Get 未命中次数
+	Sets    int64 // Set 成功次数
+	Deletes int64 // Delete 成功次数
+	Skipped int64 // 写入条件不满足而跳过的 Set 次数
+	Errors  int64 // 返回除未命中和 ErrConditionFailed 以外错误的次数
+}
+
+// 单独分配以保证 32 位平台上 64 位原子操作的对齐
+type statsCounters struct {
+	gets, hits, misses, sets, deletes, skipped, errors int64
+}
+
+func (rc *RedisCache) count(op string, errp *error) {
+	c := rc.stats
+	err := *errp
+
+	if op == opGet {
+		atomic.AddInt64(&c.gets, 1)
+		if err == nil {
+			atomic.AddInt64(&c.hits, 1)
+			return
+		}
+		if errors.Is(err, types.ErrNotFound) {
+			atomic.AddInt64(&c.misses, 1)
+			return
+		}
+	}
+	if errors.Is(err, ErrConditionFailed) {
+		atomic.AddInt64(&c.skipped, 1)
+		return
+	}
+	if err != nil {
+		atomic.AddInt64(&c.errors, 1)
+		return
+	}
+
+	switch op {
+	case opSet:
+		atomic.AddInt64(&c.sets, 1)
+	case opDelete:
+		atomic.AddInt64(&c.deletes, 1)
+	}
+}
+
+// 返回自创建或上次 ResetStats 以来 Get / Set / Delete 的计数
+func (rc *RedisCache) Stats() Stats {
+	c := rc.stats
+	if c == nil {
+		return Stats{}
+	}
+	return Stats{
+		Gets:    atomic.LoadInt64(&c.gets),
+		Hits:    atomic.LoadInt64(&c.hits),
+		Misses:  atomic.LoadInt64(&c.misses),
+		Sets:    atomic.LoadInt64(&c.sets),
+		Deletes: atomic.LoadInt64(&c.deletes),
+		Skipped: atomic.LoadInt64(&c.skipped),
+		Errors:  atomic.LoadInt64(&c.errors),
+	}
+}
+
+// 将所有计数清零
+func (rc *RedisCache) ResetStats() {
+	c := rc.stats
+	if c == nil {
+		return
+	}
+	atomic.StoreInt64(&c.gets, 0)
+	atomic.StoreInt64(&c.hits, 0)
+	atomic.StoreInt64(&c.misses, 0)
+	atomic.StoreInt64(&c.sets, 0)
+	atomic.StoreInt64(&c.deletes, 0)
+	atomic.StoreInt64(&c.skipped, 0)
+	atomic.StoreInt64(&c.errors, 0)
+}
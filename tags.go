@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/duolacloud/crud-core/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// 标签集合的键为 prefix + tagKeyPrefix + tag，集合成员为打了该标签的完整缓存键
+const tagKeyPrefix = "__tag:"
+
+// 写入缓存值，并登记到每个标签的集合中，写入和登记在同一个脚本中原子完成。
+// 缓存值有过期时间时，标签集合的过期时间延长到不短于该值；缓存值没有过期时间时，标签集合也不过期
+var setWithTagsScript = redis.NewScript(`
+local ttl = tonumber(ARGV[2])
+if ttl > 0 then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ttl)
+else
+	redis.call('SET', KEYS[1], ARGV[1])
+end
+for i = 2, #KEYS do
+	local existed = redis.call('EXISTS', KEYS[i])
+	redis.call('SADD', KEYS[i], KEYS[1])
+	if ttl <= 0 then
+		redis.call('PERSIST', KEYS[i])
+	elseif existed == 0 then
+		redis.call('PEXPIRE', KEYS[i], ttl)
+	else
+		local current = redis.call('PTTL', KEYS[i])
+		if current >= 0 and current < ttl then
+			redis.call('PEXPIRE', KEYS[i], ttl)
+		end
+	end
+end
+return 1
+`)
+
+// InvalidateTag 每批删除的成员数量
+const invalidateTagBatch = 500
+
+func (rc *RedisCache) tagKey(tag string) string {
+	return rc.prefix + tagKeyPrefix + tag
+}
+
+// 设置缓存并为其打上标签，之后可通过 InvalidateTag 删除某个标签下的所有缓存。
+// 集群模式下缓存键和标签键需要通过 hash tag 位于同一个 slot
+func (rc *RedisCache) SetWithTags(ctx context.Context, key string, value any, tags []string, opts ...cache.SetOption) error {
+	options, err := setOptions(opts)
+	if err != nil {
+		return err
+	}
+	bytes, err := rc.encode(value)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(tags)+1)
+	keys = append(keys, rc.buildKey(key))
+	for _, tag := range tags {
+		keys = append(keys, rc.tagKey(tag))
+	}
+
+	err = setWithTagsScript.Run(ctx, rc.rdb, keys, bytes, rc.expiration(options, value).Milliseconds()).Err()
+	return wrapRedisError(err)
+}
+
+// 删除标签下的所有缓存及标签集合本身。
+// 每批通过 SRANDMEMBER 读取成员，再在一个 MULTI 事务中删除这些缓存并将其移出集合，
+// 失败时该批成员仍然保留在集合中，重试即可继续删除；已自然过期的成员删除时直接忽略
+func (rc *RedisCache) InvalidateTag(ctx context.Context, tag string) error {
+	tagKey := rc.tagKey(tag)
+	for {
+		if err := ctx.Err(); err != nil {
+			return wrapRedisError(err)
+		}
+
+		cacheKeys, err := rc.rdb.SRandMemberN(ctx, tagKey, invalidateTagBatch).Result()
+		if err != nil {
+			return wrapRedisError(err)
+		}
+		if len(cacheKeys) == 0 {
+			return nil
+		}
+
+		members := make([]any, len(cacheKeys))
+		for i, cacheKey := range cacheKeys {
+			members[i] = cacheKey
+		}
+		_, err = rc.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, cacheKeys...)
+			pipe.SRem(ctx, tagKey, members...)
+			return nil
+		})
+		if err != nil {
+			return wrapRedisError(err)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/duolacloud/crud-core/cache"
+	"github.com/duolacloud/crud-core/types"
+)
+
+// 两级缓存，读取时先查 L1，未命中再查 L2 并回填 L1；写入和删除同时作用于两级
+type Tiered struct {
+	l1    cache.Cache
+	l2    cache.Cache
+	l1TTL time.Duration // 写入 L1 时的过期时间上限
+}
+
+type TieredOption func(*Tiered)
+
+// 设置写入 L1 时的过期时间上限，默认 1 分钟，L1 的值最多比 L2 旧这么久
+func WithL1TTL(ttl time.Duration) TieredOption {
+	return func(t *Tiered) {
+		t.l1TTL = ttl
+	}
+}
+
+// 创建两级缓存，l1 通常是 NewLRU 创建的内存缓存，l2 通常是 redis 缓存
+//
+//	c := cache.NewTiered(cache.NewLRU(10000, time.Minute), redisCache, cache.WithL1TTL(10*time.Second))
+func NewTiered(l1 cache.Cache, l2 cache.Cache, opts ...TieredOption) *Tiered {
+	t := &Tiered{
+		l1:    l1,
+		l2:    l2,
+		l1TTL: time.Minute,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+func (t *Tiered) Get(ctx context.Context, key string, value any, opts ...cache.GetOption) error {
+	err := t.l1.Get(ctx, key, value, opts...)
+	if !errors.Is(err, types.ErrNotFound) {
+		return err
+	}
+
+	if err := t.l2.Get(ctx, key, value, opts...); err != nil {
+		return err
+	}
+
+	// 回填失败不影响本次读取
+	_ = t.l1.Set(ctx, key, value, cache.WithExpiration(t.l1TTL))
+	return nil
+}
+
+// 先写 L2 再写 L1，L2 写入失败时不写 L1，避免 L1 中出现 L2 没有的值
+func (t *Tiered) Set(ctx context.Context, key string, value any, opts ...cache.SetOption) error {
+	if err := t.l2.Set(ctx, key, value, opts...); err != nil {
+		return err
+	}
+
+	options := &cache.SetOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	ttl := t.l1TTL
+	if options.Exipration > 0 && options.Exipration < ttl {
+		ttl = options.Exipration
+	}
+	return t.l1.Set(ctx, key, value, cache.WithExpiration(ttl))
+}
+
+// 先删 L1 再删 L2，两级都会尝试删除，返回遇到的第一个错误
+func (t *Tiered) Delete(ctx context.Context, key string, opts ...cache.DeleteOption) error {
+	err1 := t.l1.Delete(ctx, key, opts...)
+	err2 := t.l2.Delete(ctx, key, opts...)
+	if err1 != nil {
+		return err1
+	}
+	return err2
+}
+
+func (t *Tiered) Exists(ctx context.Context, key string) (bool, error) {
+	exists, err := t.l1.Exists(ctx, key)
+	if err == nil && exists {
+		return true, nil
+	}
+	return t.l2.Exists(ctx, key)
+}
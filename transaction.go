@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/duolacloud/crud-core/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// Transaction 中可用的操作，键会自动加上前缀，值会自动序列化。
+// 操作在 MULTI / EXEC 中排队，fn 返回后一起执行，其它客户端不会看到只执行了一部分的结果
+type Tx interface {
+	// 排队写入缓存，只在值序列化失败时返回错误
+	Set(key string, value any, opts ...cache.SetOption) error
+	// 排队删除缓存
+	Delete(key string) error
+}
+
+type tx struct {
+	rc      *RedisCache
+	ctx     context.Context
+	pipe    redis.Pipeliner
+	deleted []string
+	err     error
+}
+
+func (t *tx) Set(key string, value any, opts ...cache.SetOption) error {
+	options, err := setOptions(opts)
+	if err != nil {
+		if t.err == nil {
+			t.err = err
+		}
+		return err
+	}
+
+	bytes, err := t.rc.encode(value)
+	if err != nil {
+		if t.err == nil {
+			t.err = err
+		}
+		return err
+	}
+	t.pipe.Set(t.ctx, t.rc.buildKey(key), bytes, t.rc.expiration(options, value))
+	return nil
+}
+
+func (t *tx) Delete(key string) error {
+	cacheKey := t.rc.buildKey(key)
+	t.pipe.Del(t.ctx, cacheKey)
+	t.deleted = append(t.deleted, cacheKey)
+	return nil
+}
+
+// 在一个 MULTI / EXEC 事务中执行 fn 中排队的写入和删除，要么全部生效，要么都不生效。
+// fn 返回错误或有值序列化失败时放弃所有操作并返回该错误。
+// 集群模式下所有键必须位于同一个 slot，可以通过 WithKeyBuilder 加上相同的 hash tag
+func (rc *RedisCache) Transaction(ctx context.Context, fn func(tx Tx) error) error {
+	t := &tx{rc: rc, ctx: ctx, pipe: rc.rdb.TxPipeline()}
+	if err := fn(t); err != nil {
+		t.pipe.Discard()
+		return err
+	}
+	if t.err != nil {
+		t.pipe.Discard()
+		return t.err
+	}
+
+	if t.pipe.Len() == 0 {
+		return nil
+	}
+	if _, err := t.pipe.Exec(ctx); err != nil {
+		return wrapRedisError(err)
+	}
+	if rc.autoInvalidate && len(t.deleted) > 0 {
+		return wrapRedisError(rc.publishInvalidations(ctx, t.deleted))
+	}
+	return nil
+}
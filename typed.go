@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/duolacloud/crud-core/cache"
+	"github.com/duolacloud/crud-core/types"
+)
+
+// 类型安全的缓存包装，可以包装任意 cache.Cache 实现
+//
+//	users := cache.NewTyped[*User](c)
+//	user, err := users.Get(ctx, "user:1")
+type Typed[T any] struct {
+	c cache.Cache
+}
+
+func NewTyped[T any](c cache.Cache) *Typed[T] {
+	return &Typed[T]{c: c}
+}
+
+// 查询缓存，未命中时返回 T 的零值和 types.ErrNotFound
+func (t *Typed[T]) Get(ctx context.Context, key string, opts ...cache.GetOption) (T, error) {
+	var value T
+	if err := t.c.Get(ctx, key, &value, opts...); err != nil {
+		var zero T
+		return zero, err
+	}
+	return value, nil
+}
+
+func (t *Typed[T]) Set(ctx context.Context, key string, value T, opts ...cache.SetOption) error {
+	return t.c.Set(ctx, key, value, opts...)
+}
+
+func (t *Typed[T]) Delete(ctx context.Context, key string, opts ...cache.DeleteOption) error {
+	return t.c.Delete(ctx, key, opts...)
+}
+
+func (t *Typed[T]) Exists(ctx context.Context, key string) (bool, error) {
+	return t.c.Exists(ctx, key)
+}
+
+// 批量查询缓存，返回命中的键和值，未命中的键不出现在结果中，部分或全部未命中都不返回错误。
+// 个别值反序列化失败时这些键不出现在结果中，其它命中的值仍然返回，同时返回汇总的 *MultiError；
+// 只有 redis 本身出错时才返回 nil。c 为 *RedisCache 时只需一次 MGET 往返，其它实现逐个调用 Get
+func GetMap[T any](ctx context.Context, c cache.Cache, keys []string) (map[string]T, error) {
+	result := make(map[string]T, len(keys))
+
+	if rc, ok := c.(*RedisCache); ok {
+		var values []T
+		hits, err := rc.MGet(ctx, keys, &values)
+		var multiErr *MultiError
+		if err != nil && !errors.As(err, &multiErr) {
+			return nil, err
+		}
+		for i, key := range keys {
+			if hits[i] {
+				result[key] = values[i]
+			}
+		}
+		return result, err
+	}
+
+	var errs map[int]error
+	for i, key := range keys {
+		var value T
+		if err := c.Get(ctx, key, &value); err != nil {
+			if errors.Is(err, types.ErrNotFound) {
+				continue
+			}
+			if !errors.Is(err, ErrSerialization) {
+				return nil, err
+			}
+			if errs == nil {
+				errs = make(map[int]error)
+			}
+			errs[i] = err
+			continue
+		}
+		result[key] = value
+	}
+	if errs != nil {
+		return result, &MultiError{Keys: keys, Errors: errs}
+	}
+	return result, nil
+}
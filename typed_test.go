@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/duolacloud/crud-core/cache"
+	"github.com/duolacloud/crud-core/types"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestTyped(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+
+	users := NewTyped[*User](redisCache)
+	err = users.Set(context.TODO(), "typed_key1", &User{Name: "jack", Age: 18}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+
+	user, err := users.Get(context.TODO(), "typed_key1")
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", user.Name)
+
+	err = users.Delete(context.TODO(), "typed_key1")
+	assert.Nil(t, err)
+
+	user, err = users.Get(context.TODO(), "typed_key1")
+	assert.Same(t, err, types.ErrNotFound)
+	assert.Nil(t, user)
+
+	ages := NewTyped[int](redisCache)
+	err = ages.Set(context.TODO(), "typed_key2", 18, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+
+	age, err := ages.Get(context.TODO(), "typed_key2")
+	assert.Nil(t, err)
+	assert.Equal(t, 18, age)
+}
+
+func TestTypedProto(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"), WithCodec(ProtoCodec{}))
+	assert.Nil(t, err)
+
+	names := NewTyped[*wrapperspb.StringValue](redisCache)
+	err = names.Set(context.TODO(), "typed_proto", wrapperspb.String("jack"), cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+
+	name, err := names.Get(context.TODO(), "typed_proto")
+	assert.Nil(t, err)
+	assert.Equal(t, "jack", name.GetValue())
+}
+
+func TestGetMap(t *testing.T) {
+	redisCache, err := New(WithPrefix("curd-cache-redis:"))
+	assert.Nil(t, err)
+
+	err = redisCache.Set(context.TODO(), "map_key1", &User{Name: "jack"}, cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+	err = redisCache.Delete(context.TODO(), "map_key2")
+	assert.Nil(t, err)
+
+	for _, c := range []cache.Cache{redisCache, NewTiered(NewLRU(10, time.Minute), redisCache)} {
+		users, err := GetMap[*User](context.TODO(), c, []string{"map_key1", "map_key2"})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(users))
+		assert.Equal(t, "jack", users["map_key1"].Name)
+	}
+
+	// 无法反序列化的值不影响其它命中的值
+	err = redisCache.Set(context.TODO(), "map_key3", "not json", cache.WithExpiration(5*time.Second))
+	assert.Nil(t, err)
+	for _, c := range []cache.Cache{redisCache, NewTiered(NewLRU(10, time.Minute), redisCache)} {
+		users, err := GetMap[*User](context.TODO(), c, []string{"map_key1", "map_key3", "map_key2"})
+		var multiErr *MultiError
+		assert.True(t, errors.As(err, &multiErr))
+		assert.Equal(t, []int{1}, multiErr.indexes())
+		assert.Equal(t, 1, len(users))
+		assert.Equal(t, "jack", users["map_key1"].Name)
+	}
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/duolacloud/crud-core/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// SetIfNewer 写入的值以 envelopeMagic、versionEnvelope 和 20 位十进制版本号开头，
+// 固定宽度的版本号可以在 Lua 中直接按字符串比较大小
+const versionHeaderBytes = 22
+
+// 只有已有的值没有版本号，或版本号小于新版本号时才写入
+var setIfNewerScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if current and string.sub(current, 1, 2) == string.sub(ARGV[1], 1, 2) then
+	if string.sub(current, 3, 22) >= string.sub(ARGV[1], 3, 22) then
+		return 0
+	end
+end
+if tonumber(ARGV[2]) > 0 then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+else
+	redis.call('SET', KEYS[1], ARGV[1])
+end
+return 1
+`)
+
+// 带版本号写入缓存，已有的值版本号大于或等于 version 时不写入并返回 false，
+// 用于避免并发写入时旧数据覆盖新数据。version 不能为负数，写入的值可以通过 Get 正常读取
+func (rc *RedisCache) SetIfNewer(ctx context.Context, key string, value any, version int64, opts ...cache.SetOption) (bool, error) {
+	if version < 0 {
+		return false, errors.New("cache: SetIfNewer version must not be negative")
+	}
+
+	options, err := setOptions(opts)
+	if err != nil {
+		return false, err
+	}
+
+	bytes, err := rc.encode(value)
+	if err != nil {
+		return false, err
+	}
+
+	data := make([]byte, 0, versionHeaderBytes+len(bytes))
+	data = append(data, envelopeMagic, versionEnvelope)
+	data = append(data, fmt.Sprintf("%020d", version)...)
+	data = append(data, bytes...)
+
+	cacheKey := rc.buildKey(key)
+	ok, err := setIfNewerScript.Run(ctx, rc.rdb, []string{cacheKey}, data, rc.expiration(options, value).Milliseconds()).Int()
+	if err != nil {
+		return false, wrapRedisError(err)
+	}
+	return ok == 1, nil
+}
+
+// 去掉 SetIfNewer 写入的版本号头
+func stripVersion(data []byte) []byte {
+	if !hasEnvelope(data, versionEnvelope, versionHeaderBytes) {
+		return data
+	}
+	return data[versionHeaderBytes:]
+}
@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/duolacloud/crud-core/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// Warm / WarmMissing 每个 pipeline 最多携带的命令数量，避免单次请求过大
+const warmBatchSize = 500
+
+// 批量预热缓存，按 warmBatchSize 分批通过 pipeline 写入，已存在的键会被覆盖。
+// 所有值先完成编码，编码失败时不写入任何键
+func (rc *RedisCache) Warm(ctx context.Context, items map[string]any, opts ...cache.SetOption) error {
+	_, err := rc.warm(ctx, items, false, opts)
+	return err
+}
+
+// 批量预热缓存，只写入尚不存在的键，返回实际写入的数量。
+// 使用 SET NX 判断是否存在，重复执行不会覆盖已有的值
+func (rc *RedisCache) WarmMissing(ctx context.Context, items map[string]any, opts ...cache.SetOption) (int, error) {
+	return rc.warm(ctx, items, true, opts)
+}
+
+func (rc *RedisCache) warm(ctx context.Context, items map[string]any, onlyMissing bool, opts []cache.SetOption) (int, error) {
+	options, err := setOptions(opts)
+	if err != nil {
+		return 0, err
+	}
+
+	type entry struct {
+		cacheKey   string
+		bytes      []byte
+		expiration time.Duration
+	}
+	entries := make([]entry, 0, len(items))
+	for key, value := range items {
+		bytes, err := rc.encode(value)
+		if err != nil {
+			return 0, err
+		}
+		entries = append(entries, entry{rc.buildKey(key), bytes, rc.expiration(options, value)})
+	}
+
+	written := 0
+	for start := 0; start < len(entries); start += warmBatchSize {
+		end := start + warmBatchSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+
+		pipe := rc.rdb.Pipeline()
+		cmds := make([]*redis.BoolCmd, 0, end-start)
+		for _, e := range entries[start:end] {
+			if onlyMissing {
+				cmds = append(cmds, pipe.SetNX(ctx, e.cacheKey, e.bytes, e.expiration))
+			} else {
+				pipe.Set(ctx, e.cacheKey, e.bytes, e.expiration)
+			}
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return written, wrapRedisError(err)
+		}
+
+		if !onlyMissing {
+			written += end - start
+		}
+		for _, cmd := range cmds {
+			if cmd.Val() {
+				written++
+			}
+		}
+	}
+	return written, nil
+}
+
+// 并发预热缓存，键按 warmBatchSize 分块，最多 concurrency 个 goroutine 同时各自通过一个 pipeline 写入一块，
+// 已存在的键会被覆盖。与 Warm 不同，每块在写入前才编码，内存占用只与 concurrency 有关。
+// 任意键编码或写入失败、或 ctx 结束时不再开始新的块，已经开始的块会尽快结束；
+// 失败的键汇总为 *MultiError 返回，未开始的块中的键既不写入也不出现在错误中
+func (rc *RedisCache) WarmConcurrent(ctx context.Context, items map[string]any, concurrency int, opts ...cache.SetOption) error {
+	options, err := setOptions(opts)
+	if err != nil {
+		return err
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var errs map[int]error
+	fail := func(i int, err error) {
+		mu.Lock()
+		if errs == nil {
+			errs = make(map[int]error)
+		}
+		errs[i] = err
+		mu.Unlock()
+		cancel()
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for start := 0; start < len(keys); start += warmBatchSize {
+		sem <- struct{}{}
+		if ctx.Err() != nil {
+			<-sem
+			break
+		}
+
+		end := start + warmBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			rc.warmChunk(ctx, keys, start, end, items, options, fail)
+		}(start, end)
+	}
+	wg.Wait()
+
+	if errs != nil {
+		return &MultiError{Keys: keys, Errors: errs}
+	}
+	return wrapRedisError(parent.Err())
+}
+
+// 编码并通过一个 pipeline 写入 keys[start:end]，失败的键通过 fail 报告
+func (rc *RedisCache) warmChunk(ctx context.Context, keys []string, start, end int, items map[string]any, options *cache.SetOptions, fail func(i int, err error)) {
+	pipe := rc.rdb.Pipeline()
+	cmds := make([]*redis.StatusCmd, 0, end-start)
+	for i := start; i < end; i++ {
+		value := items[keys[i]]
+		bytes, err := rc.encode(value)
+		if err != nil {
+			fail(i, err)
+			return
+		}
+		cmds = append(cmds, pipe.Set(ctx, rc.buildKey(keys[i]), bytes, rc.expiration(options, value)))
+	}
+
+	_, err := pipe.Exec(ctx)
+	if err == nil {
+		return
+	}
+	reported := false
+	for j, cmd := range cmds {
+		if cmdErr := cmd.Err(); cmdErr != nil {
+			fail(start+j, wrapRedisError(cmdErr))
+			reported = true
+		}
+	}
+	if !reported {
+		fail(start, wrapRedisError(err))
+	}
+}
@@ -0,0 +1,25 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarmConcurrentEncodeError(t *testing.T) {
+	c, err := New(WithAddr("localhost:1"))
+	assert.Nil(t, err)
+	rc := c.(*RedisCache)
+	defer rc.Close()
+
+	// 编码失败时不访问 redis
+	err = rc.WarmConcurrent(context.TODO(), map[string]any{"bad": make(chan int), "good": &User{Name: "jack"}}, 4)
+	var multiErr *MultiError
+	assert.ErrorAs(t, err, &multiErr)
+	assert.Equal(t, []string{"bad", "good"}, multiErr.Keys)
+	assert.Len(t, multiErr.Errors, 1)
+	assert.ErrorIs(t, multiErr.Errors[0], ErrSerialization)
+
+	assert.Nil(t, rc.WarmConcurrent(context.TODO(), nil, 4))
+}